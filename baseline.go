@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"go_task2/validator"
+)
+
+// baselineEntry описывает ранее известную ошибку. Номер строки не
+// учитывается, чтобы правки выше по файлу не ломали сопоставление.
+type baselineEntry struct {
+	File string `json:"file"`
+	Code string `json:"code"`
+	Msg  string `json:"message"`
+}
+
+// baseline хранит число известных вхождений каждой ошибки.
+type baseline map[baselineEntry]int
+
+func loadBaseline(filename string) (baseline, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var entries []baselineEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, err
+	}
+	b := make(baseline)
+	for _, e := range entries {
+		b[e]++
+	}
+	return b, nil
+}
+
+// filter убирает ошибки, записанные в baseline, расходуя вхождения, так что
+// новые повторы той же ошибки всё равно будут показаны.
+func (b baseline) filter(file string, errs []validator.ValidationError) []validator.ValidationError {
+	var result []validator.ValidationError
+	for _, e := range errs {
+		key := baselineEntry{File: file, Code: e.Code, Msg: e.Msg}
+		if b[key] > 0 {
+			b[key]--
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func writeBaseline(filename string, entries []baselineEntry) error {
+	if entries == nil {
+		entries = []baselineEntry{}
+	}
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(content, '\n'), 0o644)
+}
@@ -1,460 +1,519 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
 
+	"go_task2/validator"
+
 	"gopkg.in/yaml.v3"
 )
 
-type ValidationError struct {
-	Line int
-	Msg  string
-}
+// defaultMaxFileSize ограничивает размер входного файла, чтобы
+// недоверенный ввод не разбирался целиком.
+const defaultMaxFileSize = 10 << 20
 
-var (
-	snakeCaseRe = regexp.MustCompile(`^[a-z0-9]+(?:_[a-z0-9]+)*$`)
-	memoryRe    = regexp.MustCompile(`^[0-9]+(Gi|Mi|Ki)$`)
-)
+// exitIOError — код возврата, когда не удалось записать отчёт.
+const exitIOError = 2
 
 func main() {
-	if len(os.Args) < 2 {
+	opts := validator.DefaultOptions()
+	colorMode := flag.String("color", "auto", "colorize output: auto, always or never")
+	format := flag.String("format", "text", "output format: text or json")
+	jsonSummary := flag.Bool("json-summary", false, "wrap json output in a summary object")
+	noDedup := flag.Bool("no-dedup-files", false, "validate a file again each time it is passed")
+	dump := flag.Bool("dump", false, "print the recognized pod structure before validation results (text format only)")
+	only := flag.String("only", "", "report only errors with this code")
+	maxFileSize := flag.Int64("max-file-size", defaultMaxFileSize, "reject files larger than this many bytes (0 disables the limit)")
+	flag.IntVar(&opts.MaxDepth, "max-depth", validator.DefaultMaxDepth, "maximum nesting depth of a document")
+	countOnly := flag.Bool("count-only", false, "print only the total number of errors across all files")
+	pathStyle := flag.String("path-style", "", "file names in text output: full, relative (to the walked directory) or base; default is base for files and relative for directories")
+	allFiles := flag.Bool("all-files", false, "validate every file found in directories, not only .yaml and .yml")
+	flag.BoolVar(&opts.NoLatest, "no-latest", false, "reject images tagged ':latest'")
+	flag.BoolVar(&opts.SemverTags, "semver-tags", false, "require image tags to look like semver (v1.2.3)")
+	flag.BoolVar(&opts.Strict, "strict", false, "reject unknown resource names in limits and requests and warn about ephemeralContainers")
+	flag.BoolVar(&opts.CheckPlatformComment, "check-platform-comment", false, "require a '# platform: linux/amd64|arm64' comment on every image")
+	flag.BoolVar(&opts.SecureDefaults, "secure-defaults", false, "require allowPrivilegeEscalation: false, readOnlyRootFilesystem: true and runAsNonRoot: true, and warn unless automountServiceAccountToken: false")
+	flag.BoolVar(&opts.Lint, "lint", false, "report advisory lint warnings")
+	flag.BoolVar(&opts.LintShell, "lint-shell", false, "warn about shell metacharacters in exec-form command and args")
+	flag.BoolVar(&opts.ContainerNameMatchesPod, "container-name-matches-pod", false, "warn when no container name equals or prefixes metadata.name")
+	flag.BoolVar(&opts.RequireLimits, "require-limits", false, "require cpu and memory limits on every container, including init containers")
+	flag.BoolVar(&opts.RequireTrailingNewline, "require-trailing-newline", false, "require every file to end with a newline")
+	flag.StringVar(&opts.QoS, "qos", "", "required QoS class of the pod: guaranteed")
+	flag.BoolVar(&opts.NoDuplicateDocs, "no-duplicate-docs", false, "report documents repeated within a file")
+	flag.BoolVar(&opts.KeyOrder, "key-order", false, "warn about top-level keys out of the order apiVersion, kind, metadata, spec")
+	flag.IntVar(&opts.Indent, "indent", 0, "warn about indentation that is not a multiple of this width or mixes tabs (0 disables)")
+	flag.Int64Var(&opts.MaxCPUCores, "max-cpu-cores", validator.DefaultMaxCPUCores, "with --lint, warn about integer cpu values above this many cores")
+	flag.Int64Var(&opts.QuotaCPU, "quota-cpu", 0, "maximum total cpu requests of a pod")
+	quotaMemory := flag.String("quota-memory", "", "maximum total memory requests of a pod, e.g. 4Gi")
+	allowedNodeLabels := flag.String("allowed-node-labels", "", "file listing permitted nodeSelector label keys, one per line")
+	imageAllowlist := flag.String("image-allowlist", "", "file listing permitted image repositories (registry/repo), one per line")
+	flag.IntVar(&opts.MaxNameLength, "max-name-length", 0, "maximum length of metadata.name (0 disables the check)")
+	baselineFile := flag.String("baseline", "", "JSON file of known errors to suppress")
+	writeBaselineFile := flag.String("write-baseline", "", "record all reported errors into this JSON file and exit successfully")
+	debug := flag.Bool("debug", false, "log which validators ran to stderr")
+	output := flag.String("output", "", "write the report to this file instead of stdout, creating parent directories")
+	watch := flag.String("watch", "", "revalidate this file whenever it changes, until interrupted")
+	emitSchema := flag.Bool("emit-schema", false, "print a JSON Schema of the validated Pod subset and exit")
+	listChecks := flag.Bool("list-checks", false, "print every check with its code and exit")
+	requiredLabels := flag.String("required-labels", "", "comma-separated label keys every pod must have")
+	requiredAnnotations := flag.String("required-annotations", "", "comma-separated annotation keys every pod must have")
+	reservedNames := flag.String("reserved-names", "", "comma-separated container names reserved for injected sidecars")
+	var registries stringList
+	flag.Var(&registries, "registry", "allowed image registry host, optionally with a policy: host=tag-ok or host=digest-only (repeatable, replaces the default)")
+	var include, exclude stringList
+	flag.Var(&include, "include", "glob of files to validate when walking directories (repeatable)")
+	flag.Var(&exclude, "exclude", "glob of files or directories to skip when walking directories (repeatable, wins over --include)")
+	flag.Parse()
+	if *listChecks {
+		printChecks(os.Stdout)
+		return
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "format has unsupported value '%s'\n", *format)
 		os.Exit(1)
 	}
-	filename := os.Args[1]
-	shortName := filepath.Base(filename)
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+	// Дерево --dump — текст, его нельзя смешивать с JSON-отчётом.
+	if *dump && *format == "json" {
+		fmt.Fprintln(os.Stderr, "--dump cannot be used with --format json")
 		os.Exit(1)
 	}
-	var root yaml.Node
-	if err := yaml.Unmarshal(content, &root); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+	switch *pathStyle {
+	case "", "full", "relative", "base":
+	default:
+		fmt.Fprintf(os.Stderr, "path-style has unsupported value '%s'\n", *pathStyle)
 		os.Exit(1)
 	}
-	errors := validatePod(&root)
-	if len(errors) > 0 {
-		for _, e := range errors {
-			if e.Line == 0 {
-				fmt.Println(e.Msg)
-			} else {
-				fmt.Printf("%s:%d %s\n", shortName, e.Line, e.Msg)
-			}
-		}
+	if opts.QoS != "" && opts.QoS != "guaranteed" {
+		fmt.Fprintf(os.Stderr, "qos has unsupported value '%s'\n", opts.QoS)
 		os.Exit(1)
 	}
-}
-
-func validatePod(root *yaml.Node) []ValidationError {
-	var errs []ValidationError
-	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
-		errs = append(errs, ValidationError{
-			Msg: "document is required",
-		})
-		return errs
+	if opts.Indent < 0 {
+		fmt.Fprintf(os.Stderr, "indent has invalid value %d\n", opts.Indent)
+		os.Exit(1)
 	}
-	doc := root.Content[0]
-	if doc.Kind != yaml.MappingNode {
-		errs = append(errs, ValidationError{
-			Line: doc.Line,
-			Msg:  "document must be object",
-		})
-		return errs
-	}
-	apiKey, apiVal := getMapField(doc, "apiVersion")
-	if apiKey == nil {
-		errs = append(errs, ValidationError{Msg: "apiVersion is required"})
-	} else {
-		if !isStringScalar(apiVal) {
-			errs = append(errs, ValidationError{
-				Line: apiKey.Line,
-				Msg:  "apiVersion must be string",
-			})
-		} else if apiVal.Value != "v1" {
-			errs = append(errs, ValidationError{
-				Line: apiKey.Line,
-				Msg:  fmt.Sprintf("apiVersion has unsupported value '%s'", apiVal.Value),
-			})
-		}
-	}
-	kindKey, kindVal := getMapField(doc, "kind")
-	if kindKey == nil {
-		errs = append(errs, ValidationError{Msg: "kind is required"})
-	} else {
-		if !isStringScalar(kindVal) {
-			errs = append(errs, ValidationError{
-				Line: kindKey.Line,
-				Msg:  "kind must be string",
-			})
-		} else if kindVal.Value != "Pod" {
-			errs = append(errs, ValidationError{
-				Line: kindKey.Line,
-				Msg:  fmt.Sprintf("kind has unsupported value '%s'", kindVal.Value),
-			})
-		}
-	}
-	metadataKey, metadataVal := getMapField(doc, "metadata")
-	if metadataKey == nil {
-		errs = append(errs, ValidationError{Msg: "metadata is required"})
-	} else {
-		if metadataVal.Kind != yaml.MappingNode {
-			errs = append(errs, ValidationError{
-				Line: metadataKey.Line,
-				Msg:  "metadata must be object",
-			})
-		} else {
-			validateMetadata(metadataVal, &errs)
-		}
-	}
-	specKey, specVal := getMapField(doc, "spec")
-	if specKey == nil {
-		errs = append(errs, ValidationError{Msg: "spec is required"})
-	} else {
-		if specVal.Kind != yaml.MappingNode {
-			errs = append(errs, ValidationError{
-				Line: specKey.Line,
-				Msg:  "spec must be object",
-			})
-		} else {
-			validateSpec(specVal, &errs)
+	if *requiredLabels != "" {
+		opts.RequiredLabels = splitList(*requiredLabels)
+	}
+	if *requiredAnnotations != "" {
+		opts.RequiredAnnotations = splitList(*requiredAnnotations)
+	}
+	if *reservedNames != "" {
+		opts.ReservedNames = toSet(splitList(*reservedNames))
+	}
+	if len(registries) > 0 {
+		hosts, policies, err := parseRegistries(registries)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+		opts.Registries = hosts
+		opts.RegistryPolicies = policies
 	}
-	return errs
-}
-
-func validateMetadata(node *yaml.Node, errs *[]ValidationError) {
-	nameKey, nameVal := getMapField(node, "name")
-	if nameKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "name is required"})
-	} else if !isStringScalar(nameVal) {
-		*errs = append(*errs, ValidationError{
-			Line: nameKey.Line,
-			Msg:  "name must be string",
-		})
-	} else if nameVal.Value == "" {
-		*errs = append(*errs, ValidationError{
-			Line: nameKey.Line,
-			Msg:  "name is required",
-		})
+	if *emitSchema {
+		if err := printSchema(os.Stdout, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if flag.NArg() < 1 && *watch == "" {
+		os.Exit(1)
+	}
+	if *debug {
+		opts.Debug = log.New(os.Stderr, "[debug] ", 0)
 	}
-	if nsKey, nsVal := getMapField(node, "namespace"); nsKey != nil {
-		if !isStringScalar(nsVal) {
-			*errs = append(*errs, ValidationError{
-				Line: nsKey.Line,
-				Msg:  "namespace must be string",
-			})
+	if *quotaMemory != "" {
+		q, ok := validator.ParseMemory(*quotaMemory)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "quota-memory has invalid format '%s'\n", *quotaMemory)
+			os.Exit(1)
 		}
+		opts.QuotaMemory = q
 	}
-	if labelsKey, labelsVal := getMapField(node, "labels"); labelsKey != nil {
-		if labelsVal.Kind != yaml.MappingNode {
-			*errs = append(*errs, ValidationError{
-				Line: labelsKey.Line,
-				Msg:  "labels must be object",
-			})
+	if *allowedNodeLabels != "" {
+		keys, err := readList(*allowedNodeLabels)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+		opts.AllowedNodeLabels = toSet(keys)
 	}
-}
-
-func validateSpec(node *yaml.Node, errs *[]ValidationError) {
-	if osKey, osVal := getMapField(node, "os"); osKey != nil {
-		if !isStringScalar(osVal) {
-			*errs = append(*errs, ValidationError{
-				Line: osKey.Line,
-				Msg:  "os must be string",
-			})
-		} else if osVal.Value != "linux" && osVal.Value != "windows" {
-			*errs = append(*errs, ValidationError{
-				Line: osKey.Line,
-				Msg:  fmt.Sprintf("os has unsupported value '%s'", osVal.Value),
-			})
-		}
-	}
-	contKey, contVal := getMapField(node, "containers")
-	if contKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "containers is required"})
-		return
+	if *imageAllowlist != "" {
+		repos, err := readList(*imageAllowlist)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		opts.ImageAllowlist = toSet(repos)
+	}
+	var known baseline
+	if *baselineFile != "" {
+		b, err := loadBaseline(*baselineFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		known = b
 	}
-	if contVal.Kind != yaml.SequenceNode {
-		*errs = append(*errs, ValidationError{
-			Line: contKey.Line,
-			Msg:  "containers must be array",
-		})
+	// Режим --watch печатает результаты только в стандартный вывод.
+	if *watch != "" {
+		if *output != "" {
+			fmt.Fprintln(os.Stderr, "--output cannot be used with --watch")
+			os.Exit(1)
+		}
+		color, err := useColor(*colorMode, os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		watchFile(os.Stdout, *watch, opts, color, *maxFileSize)
 		return
 	}
-	for _, c := range contVal.Content {
-		if c.Kind != yaml.MappingNode {
-			*errs = append(*errs, ValidationError{
-				Line: c.Line,
-				Msg:  "container must be object",
-			})
-			continue
+	out := os.Stdout
+	if *output != "" {
+		f, err := createOutput(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitIOError)
 		}
-		validateContainer(c, errs)
+		out = f
 	}
-}
-
-func validateContainer(node *yaml.Node, errs *[]ValidationError) {
-	nameKey, nameVal := getMapField(node, "name")
-	if nameKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "name is required"})
-	} else {
-		if !isStringScalar(nameVal) {
-			*errs = append(*errs, ValidationError{
-				Line: nameKey.Line,
-				Msg:  "name must be string",
-			})
-		} else if nameVal.Value == "" {
-			// ПОЛЕ ЕСТЬ, НО ПУСТОЕ -> "name is required"
-			*errs = append(*errs, ValidationError{
-				Line: nameKey.Line,
-				Msg:  "name is required",
-			})
-		} else if !snakeCaseRe.MatchString(nameVal.Value) {
-			*errs = append(*errs, ValidationError{
-				Line: nameKey.Line,
-				Msg:  fmt.Sprintf("name has invalid format '%s'", nameVal.Value),
-			})
-		}
-	}
-	imageKey, imageVal := getMapField(node, "image")
-	if imageKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "image is required"})
-	} else {
-		if !isStringScalar(imageVal) {
-			*errs = append(*errs, ValidationError{
-				Line: imageKey.Line,
-				Msg:  "image must be string",
-			})
-		} else if !isValidImage(imageVal.Value) {
-			*errs = append(*errs, ValidationError{
-				Line: imageKey.Line,
-				Msg:  fmt.Sprintf("image has invalid format '%s'", imageVal.Value),
-			})
-		}
-	}
-	if portsKey, portsVal := getMapField(node, "ports"); portsKey != nil {
-		if portsVal.Kind != yaml.SequenceNode {
-			*errs = append(*errs, ValidationError{
-				Line: portsKey.Line,
-				Msg:  "ports must be array",
-			})
-		} else {
-			for _, p := range portsVal.Content {
-				if p.Kind != yaml.MappingNode {
-					*errs = append(*errs, ValidationError{
-						Line: p.Line,
-						Msg:  "port must be object",
-					})
-					continue
-				}
-				validateContainerPort(p, errs)
+	// Запись в файл буферизуется: bufio запоминает первую ошибку записи,
+	// и она возвращается из Flush в конце.
+	var report io.Writer = out
+	var buffered *bufio.Writer
+	if out != os.Stdout {
+		buffered = bufio.NewWriter(out)
+		report = buffered
+	}
+	color, err := useColor(*colorMode, out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	files, err := expandPaths(flag.Args(), pathFilter{
+		allFiles: *allFiles,
+		include:  include,
+		exclude:  exclude,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if !*noDedup {
+		files = dedupFiles(files)
+	}
+	failed := false
+	errorCount := 0
+	var recorded []baselineEntry
+	var results []fileResult
+	// При нескольких файлах, в том числе найденных в каталоге, вывод
+	// указывает имя файла у каждой ошибки.
+	multi := len(files) != 1 || files[0].root != ""
+	for _, in := range files {
+		filename := in.path
+		if info, err := os.Stat(filename); err == nil && *maxFileSize > 0 && info.Size() > *maxFileSize {
+			fmt.Fprintf(os.Stderr, "%s: file exceeds maximum size\n", filename)
+			failed = true
+			continue
+		}
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+		root, errors, err := validator.ValidateTree(content, opts)
+		// Файлы без расширения YAML, найденные в каталоге, пропускаются,
+		// если это не YAML-объект: почти любой текст разбирается как
+		// скаляр. Явно переданный файл с ошибкой проваливает проверку.
+		if *allFiles && in.root != "" && !isYAMLFile(filename) && (err != nil || !isMappingDocument(root)) {
+			fmt.Fprintf(os.Stderr, "%s: skipped: not a YAML object\n", filename)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+			failed = true
+			continue
+		}
+		if *dump {
+			dumpPod(report, root)
+		}
+		if *only != "" {
+			errors = filterByCode(errors, *only)
+		}
+		if known != nil {
+			errors = known.filter(filename, errors)
+		}
+		if *writeBaselineFile != "" {
+			for _, e := range errors {
+				recorded = append(recorded, baselineEntry{File: filename, Code: e.Code, Msg: e.Msg})
 			}
+			continue
 		}
-	}
-	if rpKey, rpVal := getMapField(node, "readinessProbe"); rpKey != nil {
-		if rpVal.Kind != yaml.MappingNode {
-			*errs = append(*errs, ValidationError{
-				Line: rpKey.Line,
-				Msg:  "readinessProbe must be object",
-			})
+		if *countOnly {
+			errorCount += countErrors(errors)
+		} else if *format == "json" {
+			results = append(results, fileResult{File: filename, Errors: errors})
 		} else {
-			validateProbe(rpVal, errs)
+			printErrors(report, in.displayName(*pathStyle), errors, color, multi)
+		}
+		if hasErrors(errors) {
+			failed = true
 		}
 	}
-	if lpKey, lpVal := getMapField(node, "livenessProbe"); lpKey != nil {
-		if lpVal.Kind != yaml.MappingNode {
-			*errs = append(*errs, ValidationError{
-				Line: lpKey.Line,
-				Msg:  "livenessProbe must be object",
-			})
-		} else {
-			validateProbe(lpVal, errs)
-		}
-	}
-	resKey, resVal := getMapField(node, "resources")
-	if resKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "resources is required"})
-	} else {
-		if resVal.Kind != yaml.MappingNode {
-			*errs = append(*errs, ValidationError{
-				Line: resKey.Line,
-				Msg:  "resources must be object",
-			})
-		} else {
-			validateResources(resVal, errs)
+	if *countOnly && *writeBaselineFile == "" {
+		fmt.Fprintln(report, errorCount)
+	} else if *format == "json" && *writeBaselineFile == "" {
+		if err := printJSON(report, results, *jsonSummary, multi); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
 	}
+	if buffered != nil {
+		if err := buffered.Flush(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitIOError)
+		}
+		if err := out.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitIOError)
+		}
+	}
+	if *writeBaselineFile != "" {
+		if err := writeBaseline(*writeBaselineFile, recorded); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if failed {
+		os.Exit(1)
+	}
 }
 
-func validateContainerPort(node *yaml.Node, errs *[]ValidationError) {
-	cpKey, cpVal := getMapField(node, "containerPort")
-	if cpKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "containerPort is required"})
-	} else {
-		if !isIntScalar(cpVal) {
-			*errs = append(*errs, ValidationError{
-				Line: cpKey.Line,
-				Msg:  "containerPort must be int",
-			})
-		} else {
-			port, _ := strconv.Atoi(cpVal.Value)
-			if port <= 0 || port >= 65536 {
-				*errs = append(*errs, ValidationError{
-					Line: cpKey.Line,
-					Msg:  "containerPort value out of range",
-				})
+// readList читает непустые строки файла, пропуская комментарии после '#'.
+func readList(filename string) ([]string, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var items []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			items = append(items, line)
+		}
+	}
+	return items, nil
+}
+
+// parseRegistries разбирает значения --registry вида host[=policy].
+func parseRegistries(values []string) ([]string, map[string]validator.RegistryPolicy, error) {
+	var hosts []string
+	policies := make(map[string]validator.RegistryPolicy)
+	for _, v := range values {
+		host, policy, found := strings.Cut(v, "=")
+		if found {
+			p := validator.RegistryPolicy(policy)
+			if p != validator.PolicyTagOK && p != validator.PolicyDigestOnly {
+				return nil, nil, fmt.Errorf("registry policy has unsupported value '%s'", policy)
 			}
+			policies[host] = p
 		}
+		hosts = append(hosts, host)
 	}
-	if protoKey, protoVal := getMapField(node, "protocol"); protoKey != nil {
-		if !isStringScalar(protoVal) {
-			*errs = append(*errs, ValidationError{
-				Line: protoKey.Line,
-				Msg:  "protocol must be string",
-			})
-		} else if protoVal.Value != "TCP" && protoVal.Value != "UDP" {
-			*errs = append(*errs, ValidationError{
-				Line: protoKey.Line,
-				Msg:  fmt.Sprintf("protocol has unsupported value '%s'", protoVal.Value),
-			})
+	return hosts, policies, nil
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// splitList разбирает список через запятую, убирая пробелы вокруг
+// элементов и пустые элементы.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
+	return items
 }
-func validateProbe(node *yaml.Node, errs *[]ValidationError) {
-	httpKey, httpVal := getMapField(node, "httpGet")
-	if httpKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "httpGet is required"})
-		return
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
 	}
-	if httpVal.Kind != yaml.MappingNode {
-		*errs = append(*errs, ValidationError{
-			Line: httpKey.Line,
-			Msg:  "httpGet must be object",
-		})
-		return
+	return set
+}
+
+// pathFilter отбирает файлы при обходе каталогов. Исключения имеют
+// приоритет над включениями; без include подходят все файлы.
+type pathFilter struct {
+	allFiles bool
+	include  []string
+	exclude  []string
+}
+
+func (f pathFilter) excluded(rel string) bool {
+	return matchAny(f.exclude, rel)
+}
+
+func (f pathFilter) included(rel string) bool {
+	if !f.allFiles && !isYAMLFile(rel) {
+		return false
 	}
-	pathKey, pathVal := getMapField(httpVal, "path")
-	if pathKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "path is required"})
-	} else {
-		if !isStringScalar(pathVal) {
-			*errs = append(*errs, ValidationError{
-				Line: pathKey.Line,
-				Msg:  "path must be string",
-			})
-		} else if !strings.HasPrefix(pathVal.Value, "/") {
-			*errs = append(*errs, ValidationError{
-				Line: pathKey.Line,
-				Msg:  fmt.Sprintf("path has invalid format '%s'", pathVal.Value),
-			})
-		}
-	}
-	portKey, portVal := getMapField(httpVal, "port")
-	if portKey == nil {
-		*errs = append(*errs, ValidationError{Msg: "port is required"})
-	} else {
-		if !isIntScalar(portVal) {
-			*errs = append(*errs, ValidationError{
-				Line: portKey.Line,
-				Msg:  "port must be int",
-			})
-		} else {
-			port, _ := strconv.Atoi(portVal.Value)
-			if port <= 0 || port >= 65536 {
-				*errs = append(*errs, ValidationError{
-					Line: portKey.Line,
-					Msg:  "port value out of range",
-				})
-			}
+	return len(f.include) == 0 || matchAny(f.include, rel)
+}
+
+// matchAny сопоставляет шаблоны с относительным путём и с именем файла.
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, filepath.Base(rel)); ok {
+			return true
 		}
 	}
+	return false
 }
 
-func validateResources(node *yaml.Node, errs *[]ValidationError) {
-	// limits (опционально)
-	if limitsKey, limitsVal := getMapField(node, "limits"); limitsKey != nil {
-		if limitsVal.Kind != yaml.MappingNode {
-			*errs = append(*errs, ValidationError{
-				Line: limitsKey.Line,
-				Msg:  "limits must be object",
-			})
-		} else {
-			validateResourceMap(limitsVal, errs)
+// inputFile — файл для проверки; root задан, если файл найден обходом
+// каталога.
+type inputFile struct {
+	path string
+	root string
+}
+
+// displayName возвращает имя файла для текстового вывода в стиле
+// --path-style. Пустой стиль означает base для явно переданных файлов и
+// relative для найденных в каталогах.
+func (f inputFile) displayName(style string) string {
+	if style == "" {
+		style = "base"
+		if f.root != "" {
+			style = "relative"
 		}
 	}
-	if reqKey, reqVal := getMapField(node, "requests"); reqKey != nil {
-		if reqVal.Kind != yaml.MappingNode {
-			*errs = append(*errs, ValidationError{
-				Line: reqKey.Line,
-				Msg:  "requests must be object",
-			})
-		} else {
-			validateResourceMap(reqVal, errs)
+	switch style {
+	case "full":
+		if abs, err := filepath.Abs(f.path); err == nil {
+			return abs
+		}
+	case "relative":
+		if f.root == "" {
+			return f.path
+		}
+		if rel, err := filepath.Rel(f.root, f.path); err == nil {
+			return rel
 		}
 	}
+	return filepath.Base(f.path)
 }
 
-func validateResourceMap(node *yaml.Node, errs *[]ValidationError) {
-	if cpuKey, cpuVal := getMapField(node, "cpu"); cpuKey != nil {
-		if !isIntScalar(cpuVal) {
-			*errs = append(*errs, ValidationError{
-				Line: cpuKey.Line,
-				Msg:  "cpu must be int",
-			})
-		}
-	}
-	if memKey, memVal := getMapField(node, "memory"); memKey != nil {
-		if !isStringScalar(memVal) {
-			*errs = append(*errs, ValidationError{
-				Line: memKey.Line,
-				Msg:  "memory must be string",
-			})
-		} else if !memoryRe.MatchString(memVal.Value) {
-			*errs = append(*errs, ValidationError{
-				Line: memKey.Line,
-				Msg:  fmt.Sprintf("memory has invalid format '%s'", memVal.Value),
-			})
+func expandPaths(args []string, filter pathFilter) ([]inputFile, error) {
+	var files []inputFile
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			files = append(files, inputFile{path: arg})
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(arg, path)
+			if err != nil {
+				return err
+			}
+			if rel != "." && filter.excluded(rel) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() && filter.included(rel) {
+				files = append(files, inputFile{path: path, root: arg})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
+	return files, nil
 }
 
-func getMapField(m *yaml.Node, field string) (keyNode, valueNode *yaml.Node) {
-	if m.Kind != yaml.MappingNode {
-		return nil, nil
-	}
-	for i := 0; i+1 < len(m.Content); i += 2 {
-		k := m.Content[i]
-		v := m.Content[i+1]
-		if k.Value == field {
-			return k, v
+// isMappingDocument сообщает, что первый документ — YAML-объект.
+func isMappingDocument(root *yaml.Node) bool {
+	return len(root.Content) > 0 && root.Content[0].Kind == yaml.MappingNode
+}
+
+func isYAMLFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func dedupFiles(files []inputFile) []inputFile {
+	seen := make(map[string]bool)
+	var result []inputFile
+	for _, f := range files {
+		key := f.path
+		if abs, err := filepath.Abs(f.path); err == nil {
+			key = abs
 		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, f)
 	}
-	return nil, nil
+	return result
 }
 
-func isStringScalar(n *yaml.Node) bool {
-	return n.Kind == yaml.ScalarNode && n.Tag == "!!str"
+func filterByCode(errs []validator.ValidationError, code string) []validator.ValidationError {
+	var result []validator.ValidationError
+	for _, e := range errs {
+		if e.Code == code {
+			result = append(result, e)
+		}
+	}
+	return result
 }
 
-func isIntScalar(n *yaml.Node) bool {
-	return n.Kind == yaml.ScalarNode && n.Tag == "!!int"
+func hasErrors(errs []validator.ValidationError) bool {
+	return countErrors(errs) > 0
 }
 
-func isValidImage(s string) bool {
-	const prefix = "registry.bigbrother.io/"
-	if !strings.HasPrefix(s, prefix) {
-		return false
-	}
-	rest := s[len(prefix):]
-	colon := strings.LastIndex(rest, ":")
-	if colon == -1 {
-		return false
+// countErrors считает ошибки без учёта предупреждений.
+func countErrors(errs []validator.ValidationError) int {
+	n := 0
+	for _, e := range errs {
+		if e.Severity == validator.SeverityError {
+			n++
+		}
 	}
-	tag := rest[colon+1:]
-	return tag != ""
+	return n
 }
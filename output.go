@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"go_task2/validator"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// createOutput создаёт файл отчёта вместе с недостающими каталогами.
+func createOutput(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+func useColor(mode string, out *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		return isTerminal(out), nil
+	}
+	return false, fmt.Errorf("color has unsupported value '%s'", mode)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// printErrors печатает ошибки одного файла. Ошибки без строки выводятся
+// без имени файла, как раньше; withFile добавляет его и к ним, чтобы при
+// проверке нескольких файлов было видно, к какому файлу они относятся.
+func printErrors(w io.Writer, name string, errs []validator.ValidationError, color, withFile bool) {
+	for _, e := range errs {
+		msg := e.Msg
+		if e.Severity == validator.SeverityWarning {
+			msg = "warning: " + msg
+		}
+		line := msg
+		if e.Line != 0 {
+			line = fmt.Sprintf("%s:%d %s", name, e.Line, msg)
+		} else if withFile {
+			line = fmt.Sprintf("%s: %s", name, msg)
+		}
+		if color {
+			code := colorRed
+			if e.Severity == validator.SeverityWarning {
+				code = colorYellow
+			}
+			line = code + line + colorReset
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// fileResult — ошибки одного проверенного файла.
+type fileResult struct {
+	File   string                      `json:"file"`
+	Errors []validator.ValidationError `json:"errors"`
+}
+
+type jsonSummary struct {
+	File       string                      `json:"file"`
+	Errors     []validator.ValidationError `json:"errors"`
+	ErrorCount int                         `json:"errorCount"`
+	Valid      bool                        `json:"valid"`
+}
+
+// printJSON выводит результаты одним JSON-документом. Для одного файла
+// это массив ошибок (или сводка с --json-summary), для нескольких — массив
+// записей с именем файла.
+func printJSON(w io.Writer, results []fileResult, summary, multi bool) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	entries := make([]any, 0, len(results))
+	for _, r := range results {
+		if r.Errors == nil {
+			r.Errors = []validator.ValidationError{}
+		}
+		switch {
+		case summary:
+			count := countErrors(r.Errors)
+			entries = append(entries, jsonSummary{
+				File:       r.File,
+				Errors:     r.Errors,
+				ErrorCount: count,
+				Valid:      count == 0,
+			})
+		case multi:
+			entries = append(entries, r)
+		default:
+			entries = append(entries, r.Errors)
+		}
+	}
+	if !multi && len(entries) == 1 {
+		return enc.Encode(entries[0])
+	}
+	return enc.Encode(entries)
+}
+
+// dumpPod печатает распознанную структуру пода в виде дерева.
+func dumpPod(w io.Writer, root *yaml.Node) {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		fmt.Fprintln(w, "pod <empty>")
+		return
+	}
+	doc := resolveAlias(root.Content[0])
+	_, metadata := mapField(doc, "metadata")
+	fmt.Fprintf(w, "pod %s\n", dumpField(metadata, "name"))
+	_, spec := mapField(doc, "spec")
+	for _, field := range []string{"initContainers", "containers"} {
+		_, list := mapField(resolveAlias(spec), field)
+		list = resolveAlias(list)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, c := range list.Content {
+			c = resolveAlias(c)
+			fmt.Fprintf(w, "  %s %s\n", strings.TrimSuffix(field, "s"), dumpField(c, "name"))
+			fmt.Fprintf(w, "    image %s\n", dumpField(c, "image"))
+			_, ports := mapField(c, "ports")
+			ports = resolveAlias(ports)
+			if ports == nil || ports.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, p := range ports.Content {
+				p = resolveAlias(p)
+				proto := dumpField(p, "protocol")
+				if proto == "<none>" {
+					proto = "TCP"
+				}
+				fmt.Fprintf(w, "    port %s/%s\n", dumpField(p, "containerPort"), proto)
+			}
+		}
+	}
+}
+
+func dumpField(node *yaml.Node, field string) string {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return "<none>"
+	}
+	_, val := mapField(node, field)
+	val = resolveAlias(val)
+	if val == nil || val.Kind != yaml.ScalarNode {
+		return "<none>"
+	}
+	return val.Value
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+func mapField(m *yaml.Node, field string) (keyNode, valueNode *yaml.Node) {
+	m = resolveAlias(m)
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == field {
+			return m.Content[i], m.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func printChecks(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range validator.Checks {
+		enabled := "default"
+		if c.Flag != "" {
+			enabled = c.Flag
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Code, enabled, c.Description)
+	}
+	tw.Flush()
+}
+
+func printSchema(w io.Writer, opts validator.Options) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(validator.Schema(opts))
+}
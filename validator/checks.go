@@ -0,0 +1,246 @@
+package validator
+
+// Check описывает одну проверку для --list-checks. Список должен
+// совпадать с кодами, которые выдают валидаторы.
+type Check struct {
+	Code        string
+	Description string
+	// Flag — флаг, включающий проверку; пустой, если она работает всегда.
+	Flag string
+}
+
+// Checks перечисляет все коды, которые выдают валидаторы.
+var Checks = []Check{
+	{Code: "allowPrivilegeEscalation-secure", Description: "allowPrivilegeEscalation is false", Flag: "--secure-defaults"},
+	{Code: "allowPrivilegeEscalation-type", Description: "allowPrivilegeEscalation has the expected type"},
+	{Code: "annotations-required-key", Description: "metadata.annotations has every required key", Flag: "--required-annotations"},
+	{Code: "annotations-type", Description: "annotations has the expected type"},
+	{Code: "annotations-value-type", Description: "annotation values are strings"},
+	{Code: "apiVersion-kind-swapped", Description: "apiVersion and kind are not swapped"},
+	{Code: "apiVersion-required", Description: "apiVersion is present"},
+	{Code: "apiVersion-type", Description: "apiVersion has the expected type"},
+	{Code: "apiVersion-value", Description: "apiVersion has a supported value"},
+	{Code: "args-entry-type", Description: "args entries are strings"},
+	{Code: "args-shell", Description: "command and args have no shell metacharacters", Flag: "--lint-shell"},
+	{Code: "args-type", Description: "args has the expected type"},
+	{Code: "automountServiceAccountToken-secure", Description: "automountServiceAccountToken is false", Flag: "--secure-defaults"},
+	{Code: "automountServiceAccountToken-type", Description: "automountServiceAccountToken has the expected type"},
+	{Code: "capabilities-add-dangerous", Description: "capabilities.add has no dangerous capabilities", Flag: "--secure-defaults"},
+	{Code: "capabilities-add-type", Description: "capabilities.add is an array of strings"},
+	{Code: "capabilities-drop-all", Description: "capabilities.drop includes ALL", Flag: "--secure-defaults"},
+	{Code: "capabilities-drop-type", Description: "capabilities.drop is an array of strings"},
+	{Code: "capabilities-type", Description: "capabilities has the expected type"},
+	{Code: "command-entry-type", Description: "command entries are strings"},
+	{Code: "command-required", Description: "command is present"},
+	{Code: "command-type", Description: "command has the expected type"},
+	{Code: "conditionType-format", Description: "readinessGates conditionType is a qualified name"},
+	{Code: "conditionType-required", Description: "readinessGates entries have a conditionType"},
+	{Code: "conditionType-type", Description: "conditionType has the expected type"},
+	{Code: "configMapKeyRef-type", Description: "configMapKeyRef has the expected type"},
+	{Code: "configMapRef-type", Description: "configMapRef has the expected type"},
+	{Code: "container-type", Description: "container has the expected type"},
+	{Code: "containerPort-duplicate", Description: "containerPort/protocol pairs are unique within a container"},
+	{Code: "containerPort-range", Description: "containerPort is within the valid range"},
+	{Code: "containerPort-required", Description: "containerPort is present"},
+	{Code: "containerPort-type", Description: "containerPort has the expected type"},
+	{Code: "containers-pod-name", Description: "some container name equals or prefixes the pod name", Flag: "--container-name-matches-pod"},
+	{Code: "containers-required", Description: "containers is present"},
+	{Code: "containers-type", Description: "containers has the expected type"},
+	{Code: "cpu-format", Description: "cpu is a decimal integer"},
+	{Code: "cpu-limit-required", Description: "limits.cpu is set", Flag: "--require-limits"},
+	{Code: "cpu-millicores", Description: "integer cpu values are not millicores in disguise", Flag: "--lint"},
+	{Code: "cpu-type", Description: "cpu has the expected type"},
+	{Code: "dnsConfig-nameserver-format", Description: "dnsConfig.nameservers entries are IP addresses"},
+	{Code: "dnsConfig-nameservers-count", Description: "dnsConfig.nameservers has at most 3 entries"},
+	{Code: "dnsConfig-searches-count", Description: "dnsConfig.searches has at most 6 entries"},
+	{Code: "dnsConfig-type", Description: "dnsConfig has the expected type"},
+	{Code: "document-depth", Description: "document nesting stays within --max-depth"},
+	{Code: "document-duplicate", Description: "documents in a file are not repeated", Flag: "--no-duplicate-docs"},
+	{Code: "document-list", Description: "the document is a single object, not a list"},
+	{Code: "document-required", Description: "document is present"},
+	{Code: "document-type", Description: "document has the expected type"},
+	{Code: "enableServiceLinks-type", Description: "enableServiceLinks has the expected type"},
+	{Code: "env-duplicate", Description: "env variable names are unique within a container"},
+	{Code: "env-entry-type", Description: "env entries are objects"},
+	{Code: "env-overrides-envFrom", Description: "env does not override keys of a ConfigMap/Secret declared in the same file", Flag: "--lint"},
+	{Code: "env-type", Description: "env has the expected type"},
+	{Code: "envFrom-entry-type", Description: "envFrom entries are objects"},
+	{Code: "envFrom-source", Description: "envFrom entries reference exactly one ConfigMap or Secret"},
+	{Code: "envFrom-type", Description: "envFrom has the expected type"},
+	{Code: "ephemeralContainers-set", Description: "ephemeralContainers are not part of a normal Pod spec", Flag: "--strict"},
+	{Code: "ephemeralContainers-type", Description: "ephemeralContainers has the expected type"},
+	{Code: "exec-type", Description: "exec has the expected type"},
+	{Code: "failureThreshold-range", Description: "probe failureThreshold is at least 1"},
+	{Code: "failureThreshold-type", Description: "failureThreshold has the expected type"},
+	{Code: "file-indent", Description: "indentation is a multiple of the configured width without tabs", Flag: "--indent"},
+	{Code: "file-trailing-newline", Description: "file ends with a newline", Flag: "--require-trailing-newline"},
+	{Code: "hostAliases-entry-type", Description: "hostAliases entries are objects"},
+	{Code: "hostAliases-ip-format", Description: "hostAliases ip is an IP address"},
+	{Code: "hostAliases-type", Description: "hostAliases has the expected type"},
+	{Code: "hostname-type", Description: "hostname has the expected type"},
+	{Code: "hostnames-type", Description: "hostnames has the expected type"},
+	{Code: "hostPID-type", Description: "hostPID has the expected type"},
+	{Code: "httpGet-required", Description: "httpGet is present"},
+	{Code: "httpGet-type", Description: "httpGet has the expected type"},
+	{Code: "hugepages-format", Description: "hugepages-<size> resources are memory quantities"},
+	{Code: "hugepages-type", Description: "hugepages-<size> resources have the expected type"},
+	{Code: "hugepages-volume", Description: "containers requesting hugepages mount a HugePages emptyDir volume", Flag: "--lint"},
+	{Code: "image-digest-required", Description: "images from digest-only registries are pinned by digest", Flag: "--registry"},
+	{Code: "image-empty", Description: "image is not empty"},
+	{Code: "image-format", Description: "image has a valid format"},
+	{Code: "image-latest", Description: "image does not use the ':latest' tag", Flag: "--no-latest"},
+	{Code: "image-not-allowlisted", Description: "image repository is listed in the allowlist", Flag: "--image-allowlist"},
+	{Code: "image-platform-comment", Description: "image carries a '# platform: linux/<arch>' comment", Flag: "--check-platform-comment"},
+	{Code: "image-required", Description: "image is present"},
+	{Code: "image-semver", Description: "image tag follows semver", Flag: "--semver-tags"},
+	{Code: "image-shared", Description: "containers do not reuse the same image", Flag: "--lint"},
+	{Code: "image-type", Description: "image has the expected type"},
+	{Code: "image-whitespace", Description: "image contains no whitespace"},
+	{Code: "initContainer-probe", Description: "init containers declare no probes", Flag: "--lint"},
+	{Code: "initContainers-type", Description: "initContainers has the expected type"},
+	{Code: "initialDelaySeconds-range", Description: "probe initialDelaySeconds is not negative"},
+	{Code: "initialDelaySeconds-type", Description: "initialDelaySeconds has the expected type"},
+	{Code: "ip-empty", Description: "ip is not empty"},
+	{Code: "ip-required", Description: "ip is present"},
+	{Code: "ip-type", Description: "ip has the expected type"},
+	{Code: "key-format", Description: "configMapKeyRef/secretKeyRef key is a valid config key"},
+	{Code: "key-order", Description: "top-level keys follow apiVersion, kind, metadata, spec", Flag: "--key-order"},
+	{Code: "key-required", Description: "configMapKeyRef/secretKeyRef key is present"},
+	{Code: "key-type", Description: "key has the expected type"},
+	{Code: "kind-required", Description: "kind is present"},
+	{Code: "kind-type", Description: "kind has the expected type"},
+	{Code: "kind-value", Description: "kind has a supported value"},
+	{Code: "labels-required-key", Description: "metadata.labels has every required key", Flag: "--required-labels"},
+	{Code: "labels-type", Description: "labels has the expected type"},
+	{Code: "labels-value-type", Description: "label values are strings"},
+	{Code: "lifecycle-command-empty", Description: "lifecycle exec hooks have a non-empty command"},
+	{Code: "lifecycle-type", Description: "lifecycle has the expected type"},
+	{Code: "limit-without-request", Description: "every resource limit has a matching request", Flag: "--lint"},
+	{Code: "limits-required", Description: "limits is present", Flag: "--require-limits"},
+	{Code: "limits-type", Description: "limits has the expected type"},
+	{Code: "livenessProbe-type", Description: "livenessProbe has the expected type"},
+	{Code: "localhostProfile-required", Description: "localhostProfile is set for Localhost seccomp profiles"},
+	{Code: "localhostProfile-type", Description: "localhostProfile has the expected type"},
+	{Code: "localhostProfile-unexpected", Description: "localhostProfile is set only for Localhost seccomp profiles"},
+	{Code: "memory-decimal", Description: "memory is an integer quantity"},
+	{Code: "memory-format", Description: "memory has a valid format"},
+	{Code: "memory-limit-required", Description: "limits.memory is set", Flag: "--require-limits"},
+	{Code: "memory-type", Description: "memory has the expected type"},
+	{Code: "metadata-required", Description: "metadata is present"},
+	{Code: "metadata-type", Description: "metadata has the expected type"},
+	{Code: "mountPath-duplicate", Description: "mountPath is unique within a container"},
+	{Code: "mountPath-empty", Description: "mountPath is not empty"},
+	{Code: "mountPath-format", Description: "mountPath has a valid format"},
+	{Code: "mountPath-overlap", Description: "mountPaths of a container are not nested"},
+	{Code: "mountPath-required", Description: "mountPath is present"},
+	{Code: "mountPath-type", Description: "mountPath has the expected type"},
+	{Code: "name-blank", Description: "names are not whitespace only"},
+	{Code: "name-empty", Description: "name is not empty"},
+	{Code: "name-format", Description: "name has a valid format"},
+	{Code: "name-length", Description: "metadata.name fits the configured length", Flag: "--max-name-length"},
+	{Code: "name-required", Description: "name is present"},
+	{Code: "name-reserved", Description: "container name is not reserved for injected sidecars", Flag: "--reserved-names"},
+	{Code: "name-trailing-dash", Description: "metadata.name does not end with '-'", Flag: "--lint"},
+	{Code: "name-type", Description: "name has the expected type"},
+	{Code: "nameserver-type", Description: "nameserver has the expected type"},
+	{Code: "nameservers-type", Description: "nameservers has the expected type"},
+	{Code: "namespace-type", Description: "namespace has the expected type"},
+	{Code: "nodeName-format", Description: "nodeName has a valid format"},
+	{Code: "nodeName-pinned", Description: "nodeName is not used to bypass the scheduler", Flag: "--lint"},
+	{Code: "nodeName-type", Description: "nodeName has the expected type"},
+	{Code: "nodeSelector-label-overlap", Description: "pod labels do not repeat nodeSelector keys", Flag: "--lint"},
+	{Code: "nodeSelector-type", Description: "nodeSelector has the expected type"},
+	{Code: "nodeSelector-unknown-label", Description: "nodeSelector keys are in the allowed label set", Flag: "--allowed-node-labels"},
+	{Code: "nodeSelector-value-type", Description: "nodeSelector values are strings"},
+	{Code: "option-type", Description: "option has the expected type"},
+	{Code: "optional-type", Description: "optional has the expected type"},
+	{Code: "options-type", Description: "options has the expected type"},
+	{Code: "os-type", Description: "os has the expected type"},
+	{Code: "os-value", Description: "os has a supported value"},
+	{Code: "overhead-type", Description: "overhead has the expected type"},
+	{Code: "overhead-without-runtime-class", Description: "overhead is set together with runtimeClassName"},
+	{Code: "path-empty", Description: "path is not empty"},
+	{Code: "path-format", Description: "path has a valid format"},
+	{Code: "path-required", Description: "path is present"},
+	{Code: "path-type", Description: "path has the expected type"},
+	{Code: "periodSeconds-range", Description: "probe periodSeconds is at least 1"},
+	{Code: "periodSeconds-type", Description: "periodSeconds has the expected type"},
+	{Code: "port-format", Description: "port has a valid format"},
+	{Code: "port-name-duplicate", Description: "port names are unique within a container"},
+	{Code: "port-range", Description: "port is within the valid range"},
+	{Code: "port-required", Description: "port is present"},
+	{Code: "port-type", Description: "port has the expected type"},
+	{Code: "ports-type", Description: "ports has the expected type"},
+	{Code: "postStart-type", Description: "postStart has the expected type"},
+	{Code: "prefix-type", Description: "prefix has the expected type"},
+	{Code: "preStop-type", Description: "preStop has the expected type"},
+	{Code: "priority-type", Description: "priority has the expected type"},
+	{Code: "priority-without-class", Description: "priority is set together with priorityClassName"},
+	{Code: "priorityClassName-format", Description: "priorityClassName has a valid format"},
+	{Code: "priorityClassName-type", Description: "priorityClassName has the expected type"},
+	{Code: "privileged-type", Description: "privileged has the expected type"},
+	{Code: "probe-port-name-ambiguous", Description: "probes do not reference a port name declared twice"},
+	{Code: "probe-port-name-undeclared", Description: "named probe ports refer to a declared port name", Flag: "--lint"},
+	{Code: "probe-port-undeclared", Description: "numeric probe ports are declared containerPorts", Flag: "--lint"},
+	{Code: "protocol-type", Description: "protocol has the expected type"},
+	{Code: "protocol-value", Description: "protocol has a supported value"},
+	{Code: "qos-guaranteed", Description: "every container has equal cpu and memory requests and limits", Flag: "--qos"},
+	{Code: "quota-cpu", Description: "pod cpu requests fit the quota", Flag: "--quota-cpu"},
+	{Code: "quota-memory", Description: "pod memory requests fit the quota", Flag: "--quota-memory"},
+	{Code: "readinessGates-entry-type", Description: "readinessGates entries are objects"},
+	{Code: "readinessGates-type", Description: "readinessGates has the expected type"},
+	{Code: "readinessProbe-type", Description: "readinessProbe has the expected type"},
+	{Code: "readOnly-type", Description: "readOnly has the expected type"},
+	{Code: "readOnlyRootFilesystem-secure", Description: "readOnlyRootFilesystem is true", Flag: "--secure-defaults"},
+	{Code: "readOnlyRootFilesystem-type", Description: "readOnlyRootFilesystem has the expected type"},
+	{Code: "request-without-limit", Description: "every resource request has a matching limit", Flag: "--lint"},
+	{Code: "requests-type", Description: "requests has the expected type"},
+	{Code: "resource-unknown", Description: "resource names are known or extended resources", Flag: "--strict"},
+	{Code: "resources-required", Description: "resources is present"},
+	{Code: "resources-type", Description: "resources has the expected type"},
+	{Code: "runAsNonRoot-secure", Description: "runAsNonRoot is true for every container", Flag: "--secure-defaults"},
+	{Code: "runAsNonRoot-type", Description: "runAsNonRoot has the expected type"},
+	{Code: "runtimeClassName-format", Description: "runtimeClassName has a valid format"},
+	{Code: "runtimeClassName-type", Description: "runtimeClassName has the expected type"},
+	{Code: "schedulerName-format", Description: "schedulerName has a valid format"},
+	{Code: "schedulerName-type", Description: "schedulerName has the expected type"},
+	{Code: "search-type", Description: "search has the expected type"},
+	{Code: "searches-type", Description: "searches has the expected type"},
+	{Code: "seccompProfile-secure", Description: "seccompProfile is not Unconfined", Flag: "--secure-defaults"},
+	{Code: "seccompProfile-type", Description: "seccompProfile has the expected type"},
+	{Code: "seccompProfile-type-required", Description: "seccompProfile type is present"},
+	{Code: "seccompProfile-type-type", Description: "seccompProfile type is a string"},
+	{Code: "seccompProfile-type-value", Description: "seccompProfile type is RuntimeDefault, Localhost or Unconfined"},
+	{Code: "secretKeyRef-type", Description: "secretKeyRef has the expected type"},
+	{Code: "secretRef-type", Description: "secretRef has the expected type"},
+	{Code: "securityContext-type", Description: "securityContext has the expected type"},
+	{Code: "shareProcessNamespace-hostPID", Description: "shareProcessNamespace is not combined with hostPID", Flag: "--lint"},
+	{Code: "shareProcessNamespace-type", Description: "shareProcessNamespace has the expected type"},
+	{Code: "spec-required", Description: "spec is present"},
+	{Code: "spec-type", Description: "spec has the expected type"},
+	{Code: "startupProbe-type", Description: "startupProbe has the expected type"},
+	{Code: "startupProbe-window", Description: "startup probe window covers liveness initialDelaySeconds", Flag: "--lint"},
+	{Code: "stdin-type", Description: "stdin has the expected type"},
+	{Code: "stdinOnce-type", Description: "stdinOnce has the expected type"},
+	{Code: "subPath-absolute", Description: "subPath is a relative path"},
+	{Code: "subPath-exclusive", Description: "subPath and subPathExpr are not both set"},
+	{Code: "subPath-type", Description: "subPath has the expected type"},
+	{Code: "subPathExpr-type", Description: "subPathExpr has the expected type"},
+	{Code: "successThreshold-type", Description: "successThreshold has the expected type"},
+	{Code: "tcpSocket-type", Description: "tcpSocket has the expected type"},
+	{Code: "terminationMessagePath-format", Description: "terminationMessagePath has a valid format"},
+	{Code: "terminationMessagePath-type", Description: "terminationMessagePath has the expected type"},
+	{Code: "terminationMessagePolicy-type", Description: "terminationMessagePolicy has the expected type"},
+	{Code: "terminationMessagePolicy-value", Description: "terminationMessagePolicy has a supported value"},
+	{Code: "timeoutSeconds-exceeds-period", Description: "probe timeoutSeconds does not exceed periodSeconds", Flag: "--lint"},
+	{Code: "timeoutSeconds-type", Description: "timeoutSeconds has the expected type"},
+	{Code: "trailing-content", Description: "nothing follows the pod document in the file"},
+	{Code: "tty-type", Description: "tty has the expected type"},
+	{Code: "tty-without-stdin", Description: "tty is only enabled together with stdin"},
+	{Code: "value-type", Description: "value has the expected type"},
+	{Code: "valueFrom-type", Description: "env valueFrom has the expected type"},
+	{Code: "volumeMount-type", Description: "volumeMount has the expected type"},
+	{Code: "volumeMounts-type", Description: "volumeMounts has the expected type"},
+	{Code: "workingDir-format", Description: "workingDir has a valid format"},
+	{Code: "workingDir-type", Description: "workingDir has the expected type"},
+}
@@ -0,0 +1,172 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DocumentResult — ошибки одного документа потока. Index считается от нуля
+// по всем документам, включая пустые.
+type DocumentResult struct {
+	Index  int               `json:"index"`
+	Errors []ValidationError `json:"errors"`
+}
+
+// ValidateStream проверяет content по тем же правилам, что Validate, и
+// группирует ошибки по документам. В результат входят первый документ и
+// все непустые документы после него; документы не kind: Pod, кроме
+// первого, не проверяются. Ошибки ValidateContent относятся к документу, в
+// котором находится их строка, а trailing-content — к последнему.
+// Ошибка возвращается, только если не разбирается первый документ.
+func ValidateStream(content []byte, opts Options) ([]DocumentResult, error) {
+	docs, trailing, err := parseDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]DocumentResult, len(docs))
+	for i, doc := range docs {
+		results[i].Index = doc.index
+		if i == 0 || isPodDocument(doc.node) {
+			results[i].Errors = ValidateNode(doc.node, opts)
+		}
+	}
+	last := &results[len(results)-1]
+	last.Errors = append(last.Errors, trailing...)
+	for _, e := range ValidateContent(content, opts) {
+		j := 0
+		for j+1 < len(docs) && docs[j+1].node.Line <= e.Line {
+			j++
+		}
+		results[j].Errors = append(results[j].Errors, e)
+	}
+	return results, nil
+}
+
+// validateDuplicateDocuments ищет в потоке документы с одинаковым
+// содержимым. Документы сравниваются после повторной сериализации, чтобы
+// отступы и кавычки не скрывали повтор.
+func validateDuplicateDocuments(content []byte, errs *[]ValidationError) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	seen := make(map[[sha256.Size]byte]bool)
+	for i := 0; ; i++ {
+		var doc yaml.Node
+		// Ошибки разбора, в том числе после первого документа, сообщают
+		// ValidateTree и trailing-content.
+		if err := dec.Decode(&doc); err != nil {
+			return
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		canonical, err := yaml.Marshal(&doc)
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(canonical)
+		if seen[sum] {
+			*errs = append(*errs, ValidationError{
+				Line: doc.Line,
+				Code: "document-duplicate",
+				Msg:  fmt.Sprintf("duplicate document at index %d", i),
+			})
+			continue
+		}
+		seen[sum] = true
+	}
+}
+
+// lintEnvFromOverrides предупреждает, когда env контейнера переопределяет
+// переменную из envFrom. Ключи envFrom известны статически, только если
+// ConfigMap или Secret объявлен в том же файле, поэтому остальные ссылки
+// не проверяются.
+func lintEnvFromOverrides(content []byte, errs *[]ValidationError) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	// Первый документ и следующие документы kind: Pod.
+	var pods []*yaml.Node
+	// Ключи данных по "Kind/name" объявленных в файле ConfigMap и Secret.
+	inline := make(map[string][]string)
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			break
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+		if pods == nil || isPodDocument(&doc) {
+			pods = append(pods, root)
+			continue
+		}
+		_, kind := getMapField(root, "kind")
+		_, metadata := getMapField(root, "metadata")
+		_, name := getMapField(metadata, "name")
+		if !isStringScalar(kind) || !isStringScalar(name) || (kind.Value != "ConfigMap" && kind.Value != "Secret") {
+			continue
+		}
+		ref := kind.Value + "/" + name.Value
+		for _, field := range []string{"data", "stringData", "binaryData"} {
+			_, data := getMapField(root, field)
+			if data == nil || data.Kind != yaml.MappingNode {
+				continue
+			}
+			for i := 0; i+1 < len(data.Content); i += 2 {
+				inline[ref] = append(inline[ref], data.Content[i].Value)
+			}
+		}
+	}
+	if len(inline) == 0 {
+		return
+	}
+	for _, pod := range pods {
+		_, spec := getMapField(pod, "spec")
+		for _, field := range []string{"initContainers", "containers"} {
+			_, list := getMapField(spec, field)
+			if list == nil || list.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, c := range list.Content {
+				lintContainerEnvFrom(c, inline, errs)
+			}
+		}
+	}
+}
+
+func lintContainerEnvFrom(container *yaml.Node, inline map[string][]string, errs *[]ValidationError) {
+	fromEnvFrom := make(map[string]bool)
+	_, envFrom := getMapField(container, "envFrom")
+	if envFrom == nil || envFrom.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, src := range envFrom.Content {
+		prefix := ""
+		if _, p := getMapField(src, "prefix"); isStringScalar(p) {
+			prefix = p.Value
+		}
+		for ref, kind := range map[string]string{"configMapRef": "ConfigMap", "secretRef": "Secret"} {
+			_, refVal := getMapField(src, ref)
+			if _, name := getMapField(refVal, "name"); isStringScalar(name) {
+				for _, key := range inline[kind+"/"+name.Value] {
+					fromEnvFrom[prefix+key] = true
+				}
+			}
+		}
+	}
+	_, env := getMapField(container, "env")
+	if env == nil || env.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, ev := range env.Content {
+		if nameKey, name := getMapField(ev, "name"); isStringScalar(name) && fromEnvFrom[name.Value] {
+			*errs = append(*errs, ValidationError{
+				Line:     nameKey.Line,
+				Code:     "env-overrides-envFrom",
+				Msg:      fmt.Sprintf("env variable '%s' overrides a value from envFrom", name.Value),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+}
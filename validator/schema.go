@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Schema возвращает JSON Schema той части Pod, которую проверяет валидатор.
+// Шаблоны и перечисления берутся из тех же переменных, что и проверки,
+// поэтому схема не расходится с валидатором. Регулярные выражения RE2,
+// которые использует пакет, совместимы с диалектом ECMA 262 для этих шаблонов.
+func Schema(opts Options) map[string]any {
+	registries := opts.Registries
+	if len(registries) == 0 {
+		registries = []string{DefaultRegistry}
+	}
+	quoted := make([]string, len(registries))
+	for i, r := range registries {
+		quoted[i] = regexp.QuoteMeta(r)
+	}
+	name := map[string]any{"type": "string", "minLength": 1}
+	if opts.MaxNameLength > 0 {
+		name["maxLength"] = opts.MaxNameLength
+	}
+	port := map[string]any{
+		"oneOf": []any{
+			map[string]any{"type": "integer", "minimum": 1, "maximum": 65535},
+			map[string]any{"type": "string", "maxLength": 15, "pattern": portNameRe.String()},
+		},
+	}
+	handler := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"exec": map[string]any{
+				"type":     "object",
+				"required": []string{"command"},
+				"properties": map[string]any{
+					"command": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+			},
+			"httpGet": map[string]any{
+				"type":     "object",
+				"required": []string{"path", "port"},
+				"properties": map[string]any{
+					"path": map[string]any{"type": "string", "pattern": "^/"},
+					"port": map[string]any{"$ref": "#/$defs/port"},
+				},
+			},
+			"tcpSocket": map[string]any{
+				"type":     "object",
+				"required": []string{"port"},
+				"properties": map[string]any{
+					"port": map[string]any{"$ref": "#/$defs/port"},
+				},
+			},
+		},
+	}
+	resourceMap := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"cpu":    map[string]any{"type": "integer"},
+			"memory": map[string]any{"type": "string", "pattern": memoryRe.String()},
+		},
+	}
+	container := map[string]any{
+		"type":     "object",
+		"required": []string{"name", "image", "resources"},
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "pattern": snakeCaseRe.String()},
+			"image": map[string]any{
+				"type":    "string",
+				"pattern": "^(?:" + strings.Join(quoted, "|") + ")/.*:[^:]+$",
+			},
+			"workingDir": map[string]any{"type": "string", "pattern": "^/"},
+			"ports": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":     "object",
+					"required": []string{"containerPort"},
+					"properties": map[string]any{
+						"containerPort": map[string]any{"type": "integer", "minimum": 1, "maximum": 65535},
+						"name":          map[string]any{"type": "string", "maxLength": 15, "pattern": portNameRe.String()},
+						"protocol":      map[string]any{"enum": protocols},
+					},
+				},
+			},
+			"terminationMessagePath":   map[string]any{"type": "string", "pattern": "^/"},
+			"terminationMessagePolicy": map[string]any{"enum": terminationMessagePolicies},
+			"readinessProbe":           map[string]any{"$ref": "#/$defs/handler"},
+			"livenessProbe":            map[string]any{"$ref": "#/$defs/handler"},
+			"startupProbe":             map[string]any{"$ref": "#/$defs/handler"},
+			"stdin":                    map[string]any{"type": "boolean"},
+			"stdinOnce":                map[string]any{"type": "boolean"},
+			"tty":                      map[string]any{"type": "boolean"},
+			"resources": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"limits":   map[string]any{"$ref": "#/$defs/resources"},
+					"requests": map[string]any{"$ref": "#/$defs/resources"},
+				},
+			},
+		},
+	}
+	// У init-контейнеров resources необязательны.
+	initContainer := map[string]any{
+		"type":       "object",
+		"required":   []string{"name", "image"},
+		"properties": container["properties"],
+	}
+	containers := map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/container"}}
+	initContainers := map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/initContainer"}}
+	return map[string]any{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"title":    "Pod",
+		"type":     "object",
+		"required": []string{"apiVersion", "kind", "metadata", "spec"},
+		"properties": map[string]any{
+			"apiVersion": map[string]any{"const": "v1"},
+			"kind":       map[string]any{"const": "Pod"},
+			"metadata": map[string]any{
+				"type":     "object",
+				"required": []string{"name"},
+				"properties": map[string]any{
+					"name":        name,
+					"namespace":   map[string]any{"type": "string"},
+					"labels":      map[string]any{"type": "object"},
+					"annotations": map[string]any{"type": "object"},
+				},
+			},
+			"spec": map[string]any{
+				"type":     "object",
+				"required": []string{"containers"},
+				"properties": map[string]any{
+					"os":                map[string]any{"enum": osNames},
+					"nodeSelector":      map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+					"runtimeClassName":  map[string]any{"type": "string", "maxLength": 253, "pattern": dnsSubdomainRe.String()},
+					"priorityClassName": map[string]any{"type": "string", "maxLength": 253, "pattern": dnsSubdomainRe.String()},
+					"initContainers":    initContainers,
+					"containers":        containers,
+				},
+			},
+		},
+		"$defs": map[string]any{
+			"container":     container,
+			"initContainer": initContainer,
+			"handler":       handler,
+			"port":          port,
+			"resources":     resourceMap,
+		},
+	}
+}
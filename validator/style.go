@@ -0,0 +1,42 @@
+package validator
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ValidateContent выполняет проверки исходного текста файла целиком:
+// стилевые и по всем документам потока, а не только по Pod.
+func ValidateContent(content []byte, opts Options) []ValidationError {
+	var errs []ValidationError
+	if opts.RequireTrailingNewline && len(content) > 0 && content[len(content)-1] != '\n' {
+		errs = append(errs, ValidationError{
+			Line: bytes.Count(content, []byte("\n")) + 1,
+			Code: "file-trailing-newline",
+			Msg:  "file does not end with a newline",
+		})
+	}
+	if opts.Indent > 0 {
+		for i, line := range strings.Split(string(content), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			if strings.Contains(indent, "\t") || len(indent)%opts.Indent != 0 {
+				errs = append(errs, ValidationError{
+					Line:     i + 1,
+					Code:     "file-indent",
+					Msg:      "inconsistent indentation",
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+	if opts.Lint {
+		lintEnvFromOverrides(content, &errs)
+	}
+	if opts.NoDuplicateDocs {
+		validateDuplicateDocuments(content, &errs)
+	}
+	return errs
+}
@@ -0,0 +1,2975 @@
+// Package validator проверяет манифесты Kubernetes Pod, разобранные в yaml.Node.
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity отделяет ошибки от предупреждений; предупреждения не влияют на
+// код возврата CLI.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ValidationError — одно найденное нарушение. Line равен 0, если строку
+// указать нельзя (например, для отсутствующего поля).
+type ValidationError struct {
+	Line     int      `json:"line,omitempty"`
+	Code     string   `json:"code"`
+	Msg      string   `json:"message"`
+	Severity Severity `json:"severity"`
+}
+
+// Options настраивает необязательные проверки. Нулевое значение включает
+// только проверки по умолчанию.
+type Options struct {
+	// Registries перечисляет допустимые хосты реестров образов; пустой
+	// список означает DefaultRegistry.
+	Registries []string
+	// RegistryPolicies задаёт политику тегов для отдельных реестров;
+	// реестр без записи работает по PolicyTagOK.
+	RegistryPolicies map[string]RegistryPolicy
+	// Debug получает отладочные сообщения валидаторов; nil отключает их.
+	Debug         *log.Logger
+	NoLatest      bool
+	SemverTags    bool
+	Strict        bool
+	Lint          bool
+	RequireLimits bool
+	// RequireTrailingNewline требует, чтобы файл заканчивался переводом строки.
+	RequireTrailingNewline bool
+	// QoS задаёт требуемый класс QoS; поддерживается только "guaranteed".
+	QoS string
+	// MaxDepth ограничивает вложенность документа; 0 означает DefaultMaxDepth.
+	MaxDepth int
+	// LintShell предупреждает о метасимволах оболочки в command и args.
+	LintShell bool
+	// SecureDefaults включает базовый профиль безопасности контейнеров.
+	SecureDefaults bool
+	// NoDuplicateDocs запрещает одинаковые документы в одном файле.
+	NoDuplicateDocs bool
+	// KeyOrder предупреждает о ключах верхнего уровня не в каноническом порядке.
+	KeyOrder bool
+	// Indent задаёт ширину отступа для стилевой проверки; 0 отключает её.
+	Indent   int
+	QuotaCPU int64
+	// MaxCPUCores — порог, выше которого целое cpu похоже на милли-ядра;
+	// 0 означает DefaultMaxCPUCores.
+	MaxCPUCores   int64
+	QuotaMemory   int64
+	MaxNameLength int
+	// AllowedNodeLabels ограничивает ключи nodeSelector; nil отключает проверку.
+	AllowedNodeLabels map[string]bool
+	// ImageAllowlist перечисляет разрешённые репозитории образов; nil отключает проверку.
+	ImageAllowlist map[string]bool
+	// RequiredLabels перечисляет ключи, обязательные в metadata.labels.
+	RequiredLabels []string
+	// RequiredAnnotations перечисляет ключи, обязательные в metadata.annotations.
+	RequiredAnnotations []string
+	// ReservedNames перечисляет имена контейнеров, занятые внедряемыми сайдкарами.
+	ReservedNames map[string]bool
+	// CheckPlatformComment требует у образа комментарий "# platform: linux/<arch>".
+	CheckPlatformComment bool
+	// ContainerNameMatchesPod предупреждает, если ни одно имя контейнера не
+	// совпадает с metadata.name и не является его префиксом.
+	ContainerNameMatchesPod bool
+}
+
+// Допустимые значения перечислений; используются и валидатором, и схемой.
+var (
+	osNames                    = []string{"linux", "windows"}
+	protocols                  = []string{"TCP", "UDP", "SCTP"}
+	terminationMessagePolicies = []string{"File", "FallbackToLogsOnError"}
+	seccompProfileTypes        = []string{"RuntimeDefault", "Localhost", "Unconfined"}
+)
+
+var (
+	snakeCaseRe       = regexp.MustCompile(`^[a-z0-9]+(?:_[a-z0-9]+)*$`)
+	memoryRe          = regexp.MustCompile(`^([0-9]+)(Gi|Mi|Ki)?$`)
+	decimalMemoryRe   = regexp.MustCompile(`^[0-9]*\.[0-9]+(Gi|Mi|Ki)$`)
+	qualifiedNameRe   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	portNameRe        = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	platformCommentRe = regexp.MustCompile(`^#\s*platform:\s*linux/(amd64|arm64)\s*$`)
+	configKeyRe       = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	hugepagesRe       = regexp.MustCompile(`^hugepages-[0-9]+(Ki|Mi|Gi)$`)
+	semverTagRe       = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+`)
+	dnsSubdomainRe    = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// Validate разбирает YAML и проверяет документ как Pod. В многодокументном
+// файле первый документ проверяется всегда, а следующие — только с kind:
+// Pod, чтобы рядом можно было объявить ConfigMap или Secret. Ошибка
+// возвращается, только если content не является корректным YAML.
+func Validate(content []byte, opts Options) ([]ValidationError, error) {
+	_, errs, err := ValidateTree(content, opts)
+	return errs, err
+}
+
+// ValidateTree делает то же, что Validate, и дополнительно возвращает
+// первый разобранный документ, например для его вывода.
+func ValidateTree(content []byte, opts Options) (*yaml.Node, []ValidationError, error) {
+	docs, trailing, err := parseDocuments(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	errs := ValidateNode(docs[0].node, opts)
+	for _, doc := range docs[1:] {
+		if isPodDocument(doc.node) {
+			errs = append(errs, ValidateNode(doc.node, opts)...)
+		}
+	}
+	errs = append(errs, trailing...)
+	return docs[0].node, append(errs, ValidateContent(content, opts)...), nil
+}
+
+// parsedDocument — документ потока с его номером; номера считаются от нуля
+// по всем документам, включая пустые.
+type parsedDocument struct {
+	index int
+	node  *yaml.Node
+}
+
+// parseDocuments разбирает все документы content. Первый документ
+// возвращается всегда, даже пустой; пустые документы после него
+// пропускаются. Мусор после документов (содержимое после маркера "..." или
+// неразбираемый хвост) возвращается как ошибка trailing-content:
+// yaml.Unmarshal молча отбрасывает его, а это обычно склеенные файлы.
+// Ошибка возвращается, только если не разбирается сам первый документ.
+func parseDocuments(content []byte) ([]parsedDocument, []ValidationError, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	var root yaml.Node
+	if err := dec.Decode(&root); err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	docs := []parsedDocument{{0, &root}}
+	errs := contentAfterDocumentEnd(content)
+	for i := 1; ; i++ {
+		var next yaml.Node
+		err := dec.Decode(&next)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if len(errs) == 0 {
+				errs = append(errs, ValidationError{
+					Code: "trailing-content",
+					Msg:  "unexpected trailing content after document",
+				})
+			}
+			break
+		}
+		if len(next.Content) == 0 || isNullScalar(next.Content[0]) && next.Content[0].Value == "" {
+			continue
+		}
+		// содержимое после "..." уже отмечено как trailing-content
+		if len(errs) > 0 && next.Content[0].Line >= errs[0].Line {
+			break
+		}
+		docs = append(docs, parsedDocument{i, &next})
+	}
+	return docs, errs, nil
+}
+
+// contentAfterDocumentEnd ищет содержимое после маркера конца документа
+// "...", которое не начинается с "---". Такой хвост не является явно
+// начатым документом и обычно означает склеенные файлы.
+func contentAfterDocumentEnd(content []byte) []ValidationError {
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t\r") != "..." {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			next := strings.TrimSpace(lines[j])
+			if next == "" || strings.HasPrefix(next, "#") || next == "..." {
+				continue
+			}
+			if strings.HasPrefix(next, "---") || strings.HasPrefix(next, "%") {
+				break
+			}
+			return []ValidationError{{
+				Line: j + 1,
+				Code: "trailing-content",
+				Msg:  "unexpected trailing content after document",
+			}}
+		}
+	}
+	return nil
+}
+
+// isPodDocument сообщает, объявлен ли в документе kind: Pod.
+func isPodDocument(doc *yaml.Node) bool {
+	if len(doc.Content) == 0 {
+		return false
+	}
+	_, kind := getMapField(doc.Content[0], "kind")
+	return isStringScalar(kind) && kind.Value == "Pod"
+}
+
+// ValidateNode проверяет уже разобранный YAML-документ как Pod.
+func ValidateNode(root *yaml.Node, opts Options) []ValidationError {
+	var errs []ValidationError
+	defer traceValidator(opts, "ValidateNode", &errs)()
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		errs = append(errs, ValidationError{
+			Code: "document-required",
+			Msg:  "document is required",
+		})
+		return errs
+	}
+	doc := root.Content[0]
+	if depth := opts.maxDepth(); exceedsDepth(doc, depth) {
+		errs = append(errs, ValidationError{
+			Line: doc.Line,
+			Code: "document-depth",
+			Msg:  "document nesting too deep",
+		})
+		return errs
+	}
+	if doc.Kind == yaml.SequenceNode {
+		errs = append(errs, ValidationError{
+			Line: doc.Line,
+			Code: "document-list",
+			Msg:  "expected a single Pod object, got a list; did you mean multi-document with ---?",
+		})
+		return errs
+	}
+	if doc.Kind != yaml.MappingNode {
+		errs = append(errs, ValidationError{
+			Line: doc.Line,
+			Code: "document-type",
+			Msg:  "document must be object",
+		})
+		return errs
+	}
+	if opts.KeyOrder {
+		lintKeyOrder(doc, &errs)
+	}
+	apiKey, apiVal := getMapField(doc, "apiVersion")
+	kindKey, kindVal := getMapField(doc, "kind")
+	swapped := isStringScalar(apiVal) && apiVal.Value == "Pod" && isStringScalar(kindVal) && kindVal.Value == "v1"
+	if swapped {
+		errs = append(errs, ValidationError{
+			Line: apiKey.Line,
+			Code: "apiVersion-kind-swapped",
+			Msg:  "apiVersion and kind appear to be swapped",
+		})
+	} else if apiKey == nil {
+		errs = append(errs, ValidationError{Code: "apiVersion-required", Msg: "apiVersion is required"})
+	} else {
+		if !isStringScalar(apiVal) {
+			errs = append(errs, ValidationError{
+				Line: apiKey.Line,
+				Code: "apiVersion-type",
+				Msg:  "apiVersion must be string",
+			})
+		} else if apiVal.Value != "v1" {
+			errs = append(errs, ValidationError{
+				Line: apiKey.Line,
+				Code: "apiVersion-value",
+				Msg:  fmt.Sprintf("apiVersion has unsupported value '%s'", apiVal.Value),
+			})
+		}
+	}
+	if kindKey == nil {
+		errs = append(errs, ValidationError{Code: "kind-required", Msg: "kind is required"})
+	} else if !swapped {
+		if !isStringScalar(kindVal) {
+			errs = append(errs, ValidationError{
+				Line: kindKey.Line,
+				Code: "kind-type",
+				Msg:  "kind must be string",
+			})
+		} else if kindVal.Value != "Pod" {
+			errs = append(errs, ValidationError{
+				Line: kindKey.Line,
+				Code: "kind-value",
+				Msg:  fmt.Sprintf("kind has unsupported value '%s'", kindVal.Value),
+			})
+		}
+	}
+	metadataKey, metadataVal := getMapField(doc, "metadata")
+	if metadataKey == nil {
+		errs = append(errs, ValidationError{Code: "metadata-required", Msg: "metadata is required"})
+	} else {
+		if metadataVal.Kind != yaml.MappingNode {
+			errs = append(errs, ValidationError{
+				Line: metadataKey.Line,
+				Code: "metadata-type",
+				Msg:  "metadata must be object",
+			})
+		} else {
+			validateMetadata(metadataVal, opts, &errs)
+			validateRequiredKeys(metadataKey, metadataVal, "labels", opts.RequiredLabels, &errs)
+			validateRequiredKeys(metadataKey, metadataVal, "annotations", opts.RequiredAnnotations, &errs)
+		}
+	}
+	specKey, specVal := getMapField(doc, "spec")
+	if specKey == nil {
+		errs = append(errs, ValidationError{Code: "spec-required", Msg: "spec is required"})
+	} else {
+		if specVal.Kind != yaml.MappingNode {
+			errs = append(errs, ValidationError{
+				Line: specKey.Line,
+				Code: "spec-type",
+				Msg:  "spec must be object",
+			})
+		} else {
+			validateSpec(specVal, opts, &errs)
+		}
+	}
+	if opts.Lint && metadataVal != nil && metadataVal.Kind == yaml.MappingNode &&
+		specVal != nil && specVal.Kind == yaml.MappingNode {
+		lintLabelsInNodeSelector(metadataVal, specVal, &errs)
+	}
+	if opts.ContainerNameMatchesPod && metadataVal != nil && metadataVal.Kind == yaml.MappingNode &&
+		specVal != nil && specVal.Kind == yaml.MappingNode {
+		lintContainerNameMatchesPod(metadataVal, specVal, &errs)
+	}
+	return errs
+}
+
+// validateRequiredKeys сообщает об обязательных ключах, которых нет в
+// metadata.labels или metadata.annotations. Ошибка ставится на строку metadata.
+func validateRequiredKeys(metadataKey, metadata *yaml.Node, field string, required []string, errs *[]ValidationError) {
+	_, m := getOptionalField(metadata, field)
+	if m != nil && m.Kind != yaml.MappingNode {
+		return
+	}
+	for _, k := range required {
+		if key, _ := getMapField(m, k); key == nil {
+			*errs = append(*errs, ValidationError{
+				Line: metadataKey.Line,
+				Code: field + "-required-key",
+				Msg:  fmt.Sprintf("required %s '%s' is missing", strings.TrimSuffix(field, "s"), k),
+			})
+		}
+	}
+}
+
+// lintLabelsInNodeSelector предупреждает о ключах, которые есть и в labels
+// пода, и в nodeSelector: обычно это ограничение планирования, ошибочно
+// скопированное в метки.
+func lintLabelsInNodeSelector(metadata, spec *yaml.Node, errs *[]ValidationError) {
+	_, labels := getOptionalField(metadata, "labels")
+	_, selector := getOptionalField(spec, "nodeSelector")
+	if labels == nil || labels.Kind != yaml.MappingNode || selector == nil || selector.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(selector.Content); i += 2 {
+		k := selector.Content[i]
+		if lk, _ := getMapField(labels, k.Value); lk != nil {
+			*errs = append(*errs, ValidationError{
+				Line:     k.Line,
+				Code:     "nodeSelector-label-overlap",
+				Msg:      fmt.Sprintf("key '%s' appears in both labels and nodeSelector", k.Value),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+}
+
+// lintContainerNameMatchesPod предупреждает, когда ни один контейнер не
+// назван по поду: имя должно совпадать с metadata.name или быть его
+// префиксом. Имена контейнеров в snake_case, поэтому '_' сравнивается с '-'.
+func lintContainerNameMatchesPod(metadata, spec *yaml.Node, errs *[]ValidationError) {
+	_, podName := getMapField(metadata, "name")
+	containersKey, containers := getMapField(spec, "containers")
+	if !isStringScalar(podName) || podName.Value == "" || containers == nil || containers.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, c := range containers.Content {
+		if _, name := getMapField(c, "name"); isStringScalar(name) && name.Value != "" &&
+			strings.HasPrefix(podName.Value, strings.ReplaceAll(name.Value, "_", "-")) {
+			return
+		}
+	}
+	*errs = append(*errs, ValidationError{
+		Line:     containersKey.Line,
+		Code:     "containers-pod-name",
+		Msg:      fmt.Sprintf("no container name matches or prefixes pod name '%s'", podName.Value),
+		Severity: SeverityWarning,
+	})
+}
+
+// exceedsDepth сообщает, глубже ли дерево limit уровней. Обход
+// итеративный, чтобы сама проверка не переполняла стек; алиасы не
+// раскрываются.
+func exceedsDepth(root *yaml.Node, limit int) bool {
+	type item struct {
+		node  *yaml.Node
+		depth int
+	}
+	stack := []item{{root, 1}}
+	for len(stack) > 0 {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if it.depth > limit {
+			return true
+		}
+		for _, child := range it.node.Content {
+			stack = append(stack, item{child, it.depth + 1})
+		}
+	}
+	return false
+}
+
+// topLevelKeys задаёт канонический порядок ключей верхнего уровня.
+var topLevelKeys = []string{"apiVersion", "kind", "metadata", "spec"}
+
+// lintKeyOrder отмечает известный ключ, встретившийся после ключа, который
+// по канону идёт позже него. Остальные ключи не учитываются.
+func lintKeyOrder(doc *yaml.Node, errs *[]ValidationError) {
+	last := -1
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		k := doc.Content[i]
+		rank := slices.Index(topLevelKeys, k.Value)
+		if rank == -1 {
+			continue
+		}
+		if rank < last {
+			*errs = append(*errs, ValidationError{
+				Line:     k.Line,
+				Code:     "key-order",
+				Msg:      fmt.Sprintf("key '%s' is out of canonical order", k.Value),
+				Severity: SeverityWarning,
+			})
+			continue
+		}
+		last = rank
+	}
+}
+
+// traceValidator при включённой отладке пишет, сколько ошибок добавил
+// валидатор. Использование: defer traceValidator(opts, name, errs)().
+func traceValidator(opts Options, name string, errs *[]ValidationError) func() {
+	if opts.Debug == nil {
+		return func() {}
+	}
+	before := len(*errs)
+	return func() {
+		opts.Debug.Printf("%s: %d errors", name, len(*errs)-before)
+	}
+}
+
+func validateMetadata(node *yaml.Node, opts Options, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateMetadata", errs)()
+	nameKey, nameVal := getMapField(node, "name")
+	if nameKey == nil {
+		*errs = append(*errs, ValidationError{Code: "name-required", Msg: "name is required"})
+	} else if !isStringScalar(nameVal) {
+		*errs = append(*errs, ValidationError{
+			Line: nameKey.Line,
+			Code: "name-type",
+			Msg:  "name must be string",
+		})
+	} else if nameVal.Value == "" {
+		*errs = append(*errs, ValidationError{
+			Line: nameKey.Line,
+			Code: "name-required",
+			Msg:  "name is required",
+		})
+	} else if strings.TrimSpace(nameVal.Value) == "" {
+		*errs = append(*errs, ValidationError{
+			Line: nameKey.Line,
+			Code: "name-blank",
+			Msg:  "name must not be blank",
+		})
+	} else if opts.MaxNameLength > 0 && len(nameVal.Value) > opts.MaxNameLength {
+		*errs = append(*errs, ValidationError{
+			Line: nameKey.Line,
+			Code: "name-length",
+			Msg:  fmt.Sprintf("name exceeds %d characters", opts.MaxNameLength),
+		})
+	} else if lower := strings.ToLower(nameVal.Value); lower != nameVal.Value && isDNSSubdomain(lower) {
+		// Формат metadata.name целиком не проверяется, чтобы не отвергать
+		// прежде допустимые имена; отмечаются только заглавные буквы.
+		*errs = append(*errs, ValidationError{
+			Line: nameKey.Line,
+			Code: "name-format",
+			Msg:  dnsFormatMsg("name", nameVal.Value),
+		})
+	}
+	if opts.Lint && isStringScalar(nameVal) && strings.HasSuffix(nameVal.Value, "-") {
+		*errs = append(*errs, ValidationError{
+			Line:     nameKey.Line,
+			Code:     "name-trailing-dash",
+			Msg:      "name ends with '-' which may produce malformed generated names",
+			Severity: SeverityWarning,
+		})
+	}
+	if nsKey, nsVal := getMapField(node, "namespace"); nsKey != nil {
+		if !isStringScalar(nsVal) {
+			*errs = append(*errs, ValidationError{
+				Line: nsKey.Line,
+				Code: "namespace-type",
+				Msg:  "namespace must be string",
+			})
+		}
+	}
+	if labelsKey, labelsVal := getOptionalField(node, "labels"); labelsKey != nil {
+		if labelsVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: labelsKey.Line,
+				Code: "labels-type",
+				Msg:  "labels must be object",
+			})
+		} else {
+			validateMetadataValues(labelsVal, "labels", "label", errs)
+		}
+	}
+	if annKey, annVal := getOptionalField(node, "annotations"); annKey != nil {
+		if annVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: annKey.Line,
+				Code: "annotations-type",
+				Msg:  "annotations must be object",
+			})
+		} else {
+			validateMetadataValues(annVal, "annotations", "annotation", errs)
+		}
+	}
+}
+
+// validateMetadataValues требует строковые значения меток и аннотаций.
+// Для чисел вроде version: 1.2 подсказывает взять значение в кавычки.
+func validateMetadataValues(node *yaml.Node, field, noun string, errs *[]ValidationError) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if isStringScalar(v) || isNullScalar(v) {
+			continue
+		}
+		msg := fmt.Sprintf("%s value for '%s' must be string", noun, k.Value)
+		if v.Kind == yaml.ScalarNode && (v.Tag == "!!int" || v.Tag == "!!float") {
+			msg = fmt.Sprintf("%s value for '%s' must be a quoted string, got a number", noun, k.Value)
+		}
+		*errs = append(*errs, ValidationError{
+			Line: k.Line,
+			Code: field + "-value-type",
+			Msg:  msg,
+		})
+	}
+}
+
+func validateSpec(node *yaml.Node, opts Options, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateSpec", errs)()
+	if osKey, osVal := getMapField(node, "os"); osKey != nil {
+		if !isStringScalar(osVal) {
+			*errs = append(*errs, ValidationError{
+				Line: osKey.Line,
+				Code: "os-type",
+				Msg:  stringTypeMsg("os", osVal),
+			})
+		} else if !slices.Contains(osNames, osVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: osKey.Line,
+				Code: "os-value",
+				Msg:  fmt.Sprintf("os has unsupported value '%s'", osVal.Value),
+			})
+		}
+	}
+	if haKey, haVal := getMapField(node, "hostAliases"); haKey != nil {
+		if haVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: haKey.Line,
+				Code: "hostAliases-type",
+				Msg:  "hostAliases must be array",
+			})
+		} else {
+			for _, ha := range haVal.Content {
+				if ha.Kind != yaml.MappingNode {
+					*errs = append(*errs, ValidationError{
+						Line: ha.Line,
+						Code: "hostAliases-entry-type",
+						Msg:  "hostAliases entry must be object",
+					})
+					continue
+				}
+				validateHostAlias(ha, errs)
+			}
+		}
+	}
+	if nsKey, nsVal := getOptionalField(node, "nodeSelector"); nsKey != nil {
+		if nsVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: nsKey.Line,
+				Code: "nodeSelector-type",
+				Msg:  "nodeSelector must be object",
+			})
+		} else {
+			validateNodeSelector(nsVal, opts, errs)
+		}
+	}
+	rcKey, rcVal := getMapField(node, "runtimeClassName")
+	if rcKey != nil {
+		if !isStringScalar(rcVal) {
+			*errs = append(*errs, ValidationError{
+				Line: rcKey.Line,
+				Code: "runtimeClassName-type",
+				Msg:  stringTypeMsg("runtimeClassName", rcVal),
+			})
+		} else if !isDNSSubdomain(rcVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: rcKey.Line,
+				Code: "runtimeClassName-format",
+				Msg:  dnsFormatMsg("runtimeClassName", rcVal.Value),
+			})
+		}
+	}
+	if ohKey, ohVal := getOptionalField(node, "overhead"); ohKey != nil {
+		if rcKey == nil {
+			*errs = append(*errs, ValidationError{
+				Line:     ohKey.Line,
+				Code:     "overhead-without-runtime-class",
+				Msg:      "overhead set without runtimeClassName",
+				Severity: SeverityWarning,
+			})
+		}
+		if ohVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: ohKey.Line,
+				Code: "overhead-type",
+				Msg:  "overhead must be object",
+			})
+		} else {
+			validateResourceMap(ohVal, opts, errs)
+		}
+	}
+	if dnsKey, dnsVal := getOptionalField(node, "dnsConfig"); dnsKey != nil {
+		if dnsVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: dnsKey.Line,
+				Code: "dnsConfig-type",
+				Msg:  "dnsConfig must be object",
+			})
+		} else {
+			validateDNSConfig(dnsVal, errs)
+		}
+	}
+	if nnKey, nnVal := getMapField(node, "nodeName"); nnKey != nil {
+		if !isStringScalar(nnVal) {
+			*errs = append(*errs, ValidationError{
+				Line: nnKey.Line,
+				Code: "nodeName-type",
+				Msg:  stringTypeMsg("nodeName", nnVal),
+			})
+		} else if !isDNSSubdomain(nnVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: nnKey.Line,
+				Code: "nodeName-format",
+				Msg:  dnsFormatMsg("nodeName", nnVal.Value),
+			})
+		} else if opts.Lint {
+			*errs = append(*errs, ValidationError{
+				Line:     nnKey.Line,
+				Code:     "nodeName-pinned",
+				Msg:      "nodeName bypasses the scheduler",
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	if snKey, snVal := getMapField(node, "schedulerName"); snKey != nil {
+		if !isStringScalar(snVal) {
+			*errs = append(*errs, ValidationError{
+				Line: snKey.Line,
+				Code: "schedulerName-type",
+				Msg:  stringTypeMsg("schedulerName", snVal),
+			})
+		} else if !isDNSSubdomain(snVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: snKey.Line,
+				Code: "schedulerName-format",
+				Msg:  dnsFormatMsg("schedulerName", snVal.Value),
+			})
+		}
+	}
+	pcKey, pcVal := getMapField(node, "priorityClassName")
+	if pcKey != nil {
+		if !isStringScalar(pcVal) {
+			*errs = append(*errs, ValidationError{
+				Line: pcKey.Line,
+				Code: "priorityClassName-type",
+				Msg:  stringTypeMsg("priorityClassName", pcVal),
+			})
+		} else if !isDNSSubdomain(pcVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: pcKey.Line,
+				Code: "priorityClassName-format",
+				Msg:  dnsFormatMsg("priorityClassName", pcVal.Value),
+			})
+		}
+	}
+	if prioKey, prioVal := getMapField(node, "priority"); prioKey != nil {
+		if !isIntScalar(prioVal) {
+			*errs = append(*errs, ValidationError{
+				Line: prioKey.Line,
+				Code: "priority-type",
+				Msg:  "priority must be int",
+			})
+		} else if pcKey == nil {
+			*errs = append(*errs, ValidationError{
+				Line:     prioKey.Line,
+				Code:     "priority-without-class",
+				Msg:      "priority set without priorityClassName",
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	if rgKey, rgVal := getOptionalField(node, "readinessGates"); rgKey != nil {
+		if rgVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: rgKey.Line,
+				Code: "readinessGates-type",
+				Msg:  "readinessGates must be array",
+			})
+		} else {
+			for _, gate := range rgVal.Content {
+				validateReadinessGate(gate, errs)
+			}
+		}
+	}
+	if initKey, initVal := getMapField(node, "initContainers"); initKey != nil {
+		if initVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: initKey.Line,
+				Code: "initContainers-type",
+				Msg:  "initContainers must be array",
+			})
+		} else {
+			for _, c := range initVal.Content {
+				if c.Kind != yaml.MappingNode {
+					*errs = append(*errs, ValidationError{
+						Line: c.Line,
+						Code: "container-type",
+						Msg:  "container must be object",
+					})
+					continue
+				}
+				validateContainer(c, opts, true, errs)
+			}
+		}
+	}
+	if scKey, scVal := getOptionalField(node, "securityContext"); scKey != nil {
+		if scVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: scKey.Line,
+				Code: "securityContext-type",
+				Msg:  "securityContext must be object",
+			})
+		} else {
+			validateSecurityContext(scVal, errs)
+		}
+	}
+	for _, field := range []string{"shareProcessNamespace", "hostPID", "enableServiceLinks", "automountServiceAccountToken"} {
+		if key, val := getMapField(node, field); key != nil && !isBoolScalar(val) {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-type",
+				Msg:  fmt.Sprintf("%s must be bool", field),
+			})
+		}
+	}
+	if spnKey, spnVal := getMapField(node, "shareProcessNamespace"); opts.Lint && isTrueScalar(spnVal) {
+		if _, hostPID := getMapField(node, "hostPID"); isTrueScalar(hostPID) {
+			*errs = append(*errs, ValidationError{
+				Line:     spnKey.Line,
+				Code:     "shareProcessNamespace-hostPID",
+				Msg:      "shareProcessNamespace has no effect together with hostPID",
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	if ecKey, ecVal := getOptionalField(node, "ephemeralContainers"); ecKey != nil {
+		if opts.Strict {
+			*errs = append(*errs, ValidationError{
+				Line:     ecKey.Line,
+				Code:     "ephemeralContainers-set",
+				Msg:      "ephemeralContainers should not be set in a normal Pod spec",
+				Severity: SeverityWarning,
+			})
+		}
+		if ecVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: ecKey.Line,
+				Code: "ephemeralContainers-type",
+				Msg:  "ephemeralContainers must be array",
+			})
+		} else {
+			for _, c := range ecVal.Content {
+				validateEphemeralContainer(c, errs)
+			}
+		}
+	}
+	contKey, contVal := getMapField(node, "containers")
+	if contKey == nil {
+		*errs = append(*errs, ValidationError{Code: "containers-required", Msg: "containers is required"})
+		return
+	}
+	if contVal.Kind != yaml.SequenceNode {
+		*errs = append(*errs, ValidationError{
+			Line: contKey.Line,
+			Code: "containers-type",
+			Msg:  "containers must be array",
+		})
+		return
+	}
+	for _, c := range contVal.Content {
+		if c.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: c.Line,
+				Code: "container-type",
+				Msg:  "container must be object",
+			})
+			continue
+		}
+		validateContainer(c, opts, false, errs)
+	}
+	if opts.Lint {
+		lintSharedImages(contVal, errs)
+		lintHugepagesVolumes(node, errs)
+	}
+	if opts.SecureDefaults {
+		validateSecureDefaults(node, errs)
+	}
+	if opts.QuotaCPU > 0 || opts.QuotaMemory > 0 {
+		validateQuota(node, opts, errs)
+	}
+}
+
+// validateSecurityContext проверяет типы полей securityContext пода или
+// контейнера.
+func validateSecurityContext(node *yaml.Node, errs *[]ValidationError) {
+	for _, field := range []string{"allowPrivilegeEscalation", "privileged", "readOnlyRootFilesystem", "runAsNonRoot"} {
+		if key, val := getMapField(node, field); key != nil && !isBoolScalar(val) {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-type",
+				Msg:  fmt.Sprintf("%s must be bool", field),
+			})
+		}
+	}
+	if spKey, spVal := getOptionalField(node, "seccompProfile"); spKey != nil {
+		validateSeccompProfile(spKey, spVal, errs)
+	}
+	if capKey, capVal := getOptionalField(node, "capabilities"); capKey != nil {
+		if capVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: capKey.Line,
+				Code: "capabilities-type",
+				Msg:  "capabilities must be object",
+			})
+			return
+		}
+		for _, field := range []string{"add", "drop"} {
+			key, val := getOptionalField(capVal, field)
+			if key == nil {
+				continue
+			}
+			if val.Kind != yaml.SequenceNode {
+				*errs = append(*errs, ValidationError{
+					Line: key.Line,
+					Code: "capabilities-" + field + "-type",
+					Msg:  fmt.Sprintf("capabilities.%s must be array", field),
+				})
+				continue
+			}
+			for _, item := range val.Content {
+				if !isStringScalar(item) {
+					*errs = append(*errs, ValidationError{
+						Line: item.Line,
+						Code: "capabilities-" + field + "-type",
+						Msg:  fmt.Sprintf("capabilities.%s entry must be string", field),
+					})
+				}
+			}
+		}
+	}
+}
+
+func validateSeccompProfile(key, node *yaml.Node, errs *[]ValidationError) {
+	if node.Kind != yaml.MappingNode {
+		*errs = append(*errs, ValidationError{
+			Line: key.Line,
+			Code: "seccompProfile-type",
+			Msg:  "seccompProfile must be object",
+		})
+		return
+	}
+	typeKey, typeVal := getMapField(node, "type")
+	if typeKey == nil {
+		*errs = append(*errs, ValidationError{
+			Line: key.Line,
+			Code: "seccompProfile-type-required",
+			Msg:  "seccompProfile type is required",
+		})
+		return
+	}
+	if !isStringScalar(typeVal) {
+		*errs = append(*errs, ValidationError{
+			Line: typeKey.Line,
+			Code: "seccompProfile-type-type",
+			Msg:  "seccompProfile type must be string",
+		})
+		return
+	}
+	if !slices.Contains(seccompProfileTypes, typeVal.Value) {
+		*errs = append(*errs, ValidationError{
+			Line: typeKey.Line,
+			Code: "seccompProfile-type-value",
+			Msg:  fmt.Sprintf("seccompProfile type has unsupported value '%s'", typeVal.Value),
+		})
+		return
+	}
+	lpKey, lpVal := getMapField(node, "localhostProfile")
+	switch {
+	case typeVal.Value == "Localhost" && lpKey == nil:
+		*errs = append(*errs, ValidationError{
+			Line: typeKey.Line,
+			Code: "localhostProfile-required",
+			Msg:  "localhostProfile is required when seccompProfile type is Localhost",
+		})
+	case lpKey != nil && !isStringScalar(lpVal):
+		*errs = append(*errs, ValidationError{
+			Line: lpKey.Line,
+			Code: "localhostProfile-type",
+			Msg:  stringTypeMsg("localhostProfile", lpVal),
+		})
+	case lpKey != nil && typeVal.Value != "Localhost":
+		*errs = append(*errs, ValidationError{
+			Line: lpKey.Line,
+			Code: "localhostProfile-unexpected",
+			Msg:  "localhostProfile is only allowed when seccompProfile type is Localhost",
+		})
+	}
+}
+
+// lintUnconfinedSeccomp в режиме --secure-defaults запрещает seccompProfile
+// типа Unconfined.
+func lintUnconfinedSeccomp(sc *yaml.Node, errs *[]ValidationError) {
+	_, sp := getMapField(sc, "seccompProfile")
+	if typeKey, typeVal := getMapField(sp, "type"); isStringScalar(typeVal) && typeVal.Value == "Unconfined" {
+		*errs = append(*errs, ValidationError{
+			Line: typeKey.Line,
+			Code: "seccompProfile-secure",
+			Msg:  "seccompProfile type must be RuntimeDefault or Localhost",
+		})
+	}
+}
+
+// sequenceContains сообщает, есть ли в последовательности строка s.
+func sequenceContains(seq *yaml.Node, s string) bool {
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return false
+	}
+	for _, item := range seq.Content {
+		if isStringScalar(item) && item.Value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// dangerousCapabilities — возможности, которые фактически дают контейнеру
+// права root на узле.
+var dangerousCapabilities = []string{"ALL", "SYS_ADMIN", "NET_ADMIN", "SYS_PTRACE", "SYS_MODULE", "DAC_READ_SEARCH"}
+
+// validateSecureDefaults применяет базовый профиль безопасности ко всем
+// контейнерам пода. runAsNonRoot наследуется из securityContext пода.
+// Токен сервисного аккаунта монтируется по умолчанию, поэтому отсутствие
+// automountServiceAccountToken тоже даёт предупреждение.
+func validateSecureDefaults(spec *yaml.Node, errs *[]ValidationError) {
+	if k, v := getMapField(spec, "automountServiceAccountToken"); k == nil || isTrueScalar(v) {
+		e := ValidationError{
+			Code:     "automountServiceAccountToken-secure",
+			Msg:      "consider setting automountServiceAccountToken: false",
+			Severity: SeverityWarning,
+		}
+		if k != nil {
+			e.Line = k.Line
+		}
+		*errs = append(*errs, e)
+	}
+	_, podSC := getOptionalField(spec, "securityContext")
+	_, podNonRoot := getMapField(podSC, "runAsNonRoot")
+	lintUnconfinedSeccomp(podSC, errs)
+	for _, field := range []string{"initContainers", "containers"} {
+		_, list := getMapField(spec, field)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, c := range list.Content {
+			if c.Kind != yaml.MappingNode {
+				continue
+			}
+			line := c.Line
+			scKey, sc := getOptionalField(c, "securityContext")
+			if scKey != nil {
+				line = scKey.Line
+			}
+			if sc != nil && sc.Kind != yaml.MappingNode {
+				continue
+			}
+			if _, v := getMapField(sc, "allowPrivilegeEscalation"); !isBoolScalar(v) || isTrueScalar(v) {
+				*errs = append(*errs, ValidationError{
+					Line: line,
+					Code: "allowPrivilegeEscalation-secure",
+					Msg:  "allowPrivilegeEscalation should be false",
+				})
+			}
+			if _, v := getMapField(sc, "readOnlyRootFilesystem"); !isTrueScalar(v) {
+				*errs = append(*errs, ValidationError{
+					Line: line,
+					Code: "readOnlyRootFilesystem-secure",
+					Msg:  "readOnlyRootFilesystem should be true",
+				})
+			}
+			lintUnconfinedSeccomp(sc, errs)
+			_, caps := getMapField(sc, "capabilities")
+			_, drop := getMapField(caps, "drop")
+			if !sequenceContains(drop, "ALL") {
+				*errs = append(*errs, ValidationError{
+					Line: line,
+					Code: "capabilities-drop-all",
+					Msg:  "capabilities.drop must include ALL",
+				})
+			}
+			if _, add := getMapField(caps, "add"); add != nil && add.Kind == yaml.SequenceNode {
+				for _, item := range add.Content {
+					if isStringScalar(item) && slices.Contains(dangerousCapabilities, item.Value) {
+						*errs = append(*errs, ValidationError{
+							Line:     item.Line,
+							Code:     "capabilities-add-dangerous",
+							Msg:      fmt.Sprintf("capabilities.add contains dangerous capability '%s'", item.Value),
+							Severity: SeverityWarning,
+						})
+					}
+				}
+			}
+			nonRoot := podNonRoot
+			if k, v := getMapField(sc, "runAsNonRoot"); k != nil {
+				nonRoot = v
+			}
+			if !isTrueScalar(nonRoot) {
+				*errs = append(*errs, ValidationError{
+					Line: line,
+					Code: "runAsNonRoot-secure",
+					Msg:  "runAsNonRoot should be true",
+				})
+			}
+		}
+	}
+}
+
+// lintHugepagesVolumes предупреждает о контейнерах, которые запрашивают
+// hugepages-*, но не монтируют том emptyDir с medium HugePages[-<size>].
+func lintHugepagesVolumes(spec *yaml.Node, errs *[]ValidationError) {
+	hugepagesVolumes := make(map[string]bool)
+	if _, volumes := getMapField(spec, "volumes"); volumes != nil && volumes.Kind == yaml.SequenceNode {
+		for _, v := range volumes.Content {
+			_, name := getMapField(v, "name")
+			_, emptyDir := getMapField(v, "emptyDir")
+			_, medium := getMapField(emptyDir, "medium")
+			if isStringScalar(name) && isStringScalar(medium) && strings.HasPrefix(medium.Value, "HugePages") {
+				hugepagesVolumes[name.Value] = true
+			}
+		}
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		_, list := getMapField(spec, field)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, c := range list.Content {
+			resKey, res := getMapField(c, "resources")
+			if !requestsHugepages(res) || mountsAny(c, hugepagesVolumes) {
+				continue
+			}
+			*errs = append(*errs, ValidationError{
+				Line:     resKey.Line,
+				Code:     "hugepages-volume",
+				Msg:      "hugepages requested without a hugepages volume mount",
+				Severity: SeverityWarning,
+			})
+		}
+	}
+}
+
+func requestsHugepages(resources *yaml.Node) bool {
+	for _, field := range []string{"limits", "requests"} {
+		_, m := getMapField(resources, field)
+		if m == nil || m.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			if hugepagesRe.MatchString(m.Content[i].Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mountsAny сообщает, монтирует ли контейнер хотя бы один из томов.
+func mountsAny(container *yaml.Node, volumes map[string]bool) bool {
+	_, mounts := getMapField(container, "volumeMounts")
+	if mounts == nil || mounts.Kind != yaml.SequenceNode {
+		return false
+	}
+	for _, vm := range mounts.Content {
+		if _, name := getMapField(vm, "name"); isStringScalar(name) && volumes[name.Value] {
+			return true
+		}
+	}
+	return false
+}
+
+// validateEphemeralContainer проверяет только структуру отладочного
+// контейнера: у него нет ресурсов, портов и проб, обязательных для обычных.
+func validateEphemeralContainer(node *yaml.Node, errs *[]ValidationError) {
+	if node.Kind != yaml.MappingNode {
+		*errs = append(*errs, ValidationError{
+			Line: node.Line,
+			Code: "container-type",
+			Msg:  "container must be object",
+		})
+		return
+	}
+	for _, field := range []string{"name", "image"} {
+		key, val := getMapField(node, field)
+		if key == nil {
+			*errs = append(*errs, ValidationError{Code: field + "-required", Msg: field + " is required"})
+		} else if !isStringScalar(val) {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-type",
+				Msg:  fmt.Sprintf("%s must be string", field),
+			})
+		}
+	}
+}
+
+// lintSharedImages предупреждает, когда два контейнера пода используют
+// один и тот же образ: чаще всего это ошибка шаблонизации.
+func lintSharedImages(containers *yaml.Node, errs *[]ValidationError) {
+	first := make(map[string]string)
+	for _, c := range containers.Content {
+		_, imageVal := getMapField(c, "image")
+		_, nameVal := getMapField(c, "name")
+		if !isStringScalar(imageVal) || imageVal.Value == "" || !isStringScalar(nameVal) {
+			continue
+		}
+		if prev, ok := first[imageVal.Value]; ok {
+			*errs = append(*errs, ValidationError{
+				Line:     c.Line,
+				Code:     "image-shared",
+				Msg:      fmt.Sprintf("containers %s and %s use the same image '%s'", prev, nameVal.Value, imageVal.Value),
+				Severity: SeverityWarning,
+			})
+			continue
+		}
+		first[imageVal.Value] = nameVal.Value
+	}
+}
+
+// validateQuota сравнивает эффективные запросы пода с квотой: сумма по
+// обычным контейнерам, но не меньше максимума по init-контейнерам.
+func validateQuota(spec *yaml.Node, opts Options, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateQuota", errs)()
+	var cpu, mem, initCPU, initMem int64
+	if _, contVal := getMapField(spec, "containers"); contVal != nil && contVal.Kind == yaml.SequenceNode {
+		for _, container := range contVal.Content {
+			c, m := containerRequests(container)
+			cpu += c
+			mem += m
+		}
+	}
+	if _, initVal := getMapField(spec, "initContainers"); initVal != nil && initVal.Kind == yaml.SequenceNode {
+		for _, container := range initVal.Content {
+			c, m := containerRequests(container)
+			initCPU = max(initCPU, c)
+			initMem = max(initMem, m)
+		}
+	}
+	cpu = max(cpu, initCPU)
+	mem = max(mem, initMem)
+	if opts.QuotaCPU > 0 && cpu > opts.QuotaCPU {
+		*errs = append(*errs, ValidationError{
+			Code: "quota-cpu",
+			Msg:  fmt.Sprintf("pod cpu requests %d exceed quota %d", cpu, opts.QuotaCPU),
+		})
+	}
+	if opts.QuotaMemory > 0 && mem > opts.QuotaMemory {
+		*errs = append(*errs, ValidationError{
+			Code: "quota-memory",
+			Msg:  fmt.Sprintf("pod memory requests %s exceed quota %s", formatMemory(mem), formatMemory(opts.QuotaMemory)),
+		})
+	}
+}
+
+func containerRequests(container *yaml.Node) (cpu, memory int64) {
+	_, resVal := getMapField(container, "resources")
+	if resVal == nil {
+		return 0, 0
+	}
+	_, reqVal := getMapField(resVal, "requests")
+	if reqVal == nil {
+		return 0, 0
+	}
+	if _, cpuVal := getMapField(reqVal, "cpu"); cpuVal != nil && isIntScalar(cpuVal) {
+		cpu, _ = strconv.ParseInt(cpuVal.Value, 10, 64)
+	}
+	if _, memVal := getMapField(reqVal, "memory"); memVal != nil && isStringScalar(memVal) {
+		memory, _ = ParseMemory(memVal.Value)
+	}
+	return cpu, memory
+}
+
+func validateNodeSelector(node *yaml.Node, opts Options, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateNodeSelector", errs)()
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if !isStringScalar(v) {
+			*errs = append(*errs, ValidationError{
+				Line: k.Line,
+				Code: "nodeSelector-value-type",
+				Msg:  fmt.Sprintf("nodeSelector value for '%s' must be string", k.Value),
+			})
+		}
+		if opts.AllowedNodeLabels != nil && !opts.AllowedNodeLabels[k.Value] {
+			*errs = append(*errs, ValidationError{
+				Line: k.Line,
+				Code: "nodeSelector-unknown-label",
+				Msg:  fmt.Sprintf("nodeSelector uses unknown label key '%s'", k.Value),
+			})
+		}
+	}
+}
+
+func validateReadinessGate(node *yaml.Node, errs *[]ValidationError) {
+	if node.Kind != yaml.MappingNode {
+		*errs = append(*errs, ValidationError{
+			Line: node.Line,
+			Code: "readinessGates-entry-type",
+			Msg:  "readinessGates entry must be object",
+		})
+		return
+	}
+	ctKey, ctVal := getMapField(node, "conditionType")
+	if ctKey == nil {
+		*errs = append(*errs, ValidationError{
+			Line: node.Line,
+			Code: "conditionType-required",
+			Msg:  "readinessGates entry must have conditionType",
+		})
+	} else if !isStringScalar(ctVal) {
+		*errs = append(*errs, ValidationError{
+			Line: ctKey.Line,
+			Code: "conditionType-type",
+			Msg:  stringTypeMsg("conditionType", ctVal),
+		})
+	} else if !isQualifiedName(ctVal.Value) {
+		*errs = append(*errs, ValidationError{
+			Line: ctKey.Line,
+			Code: "conditionType-format",
+			Msg:  fmt.Sprintf("conditionType '%s' is invalid", ctVal.Value),
+		})
+	}
+}
+
+func validateHostAlias(node *yaml.Node, errs *[]ValidationError) {
+	ipKey, ipVal := getMapField(node, "ip")
+	if ipKey == nil {
+		*errs = append(*errs, ValidationError{Code: "ip-required", Msg: "ip is required"})
+	} else if !isStringScalar(ipVal) {
+		*errs = append(*errs, ValidationError{
+			Line: ipKey.Line,
+			Code: "ip-type",
+			Msg:  stringTypeMsg("ip", ipVal),
+		})
+	} else if ipVal.Value == "" {
+		*errs = append(*errs, ValidationError{
+			Line: ipKey.Line,
+			Code: "ip-empty",
+			Msg:  "ip must not be empty",
+		})
+	} else if net.ParseIP(ipVal.Value) == nil {
+		*errs = append(*errs, ValidationError{
+			Line: ipKey.Line,
+			Code: "hostAliases-ip-format",
+			Msg:  fmt.Sprintf("hostAliases entry ip is invalid '%s'", ipVal.Value),
+		})
+	}
+	if hnKey, hnVal := getMapField(node, "hostnames"); hnKey != nil {
+		if hnVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: hnKey.Line,
+				Code: "hostnames-type",
+				Msg:  "hostnames must be array",
+			})
+		} else {
+			for _, hn := range hnVal.Content {
+				if !isStringScalar(hn) {
+					*errs = append(*errs, ValidationError{
+						Line: hn.Line,
+						Code: "hostname-type",
+						Msg:  stringTypeMsg("hostname", hn),
+					})
+				}
+			}
+		}
+	}
+}
+
+func validateDNSConfig(node *yaml.Node, errs *[]ValidationError) {
+	if nsKey, nsVal := getMapField(node, "nameservers"); nsKey != nil {
+		if nsVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: nsKey.Line,
+				Code: "nameservers-type",
+				Msg:  "nameservers must be array",
+			})
+		} else {
+			if len(nsVal.Content) > 3 {
+				*errs = append(*errs, ValidationError{
+					Line: nsKey.Line,
+					Code: "dnsConfig-nameservers-count",
+					Msg:  "dnsConfig.nameservers exceeds 3 entries",
+				})
+			}
+			for _, ns := range nsVal.Content {
+				if !isStringScalar(ns) {
+					*errs = append(*errs, ValidationError{
+						Line: ns.Line,
+						Code: "nameserver-type",
+						Msg:  stringTypeMsg("nameserver", ns),
+					})
+				} else if net.ParseIP(ns.Value) == nil {
+					*errs = append(*errs, ValidationError{
+						Line: ns.Line,
+						Code: "dnsConfig-nameserver-format",
+						Msg:  fmt.Sprintf("dnsConfig nameserver is invalid '%s'", ns.Value),
+					})
+				}
+			}
+		}
+	}
+	if searchKey, searchVal := getMapField(node, "searches"); searchKey != nil {
+		if searchVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: searchKey.Line,
+				Code: "searches-type",
+				Msg:  "searches must be array",
+			})
+		} else {
+			if len(searchVal.Content) > 6 {
+				*errs = append(*errs, ValidationError{
+					Line: searchKey.Line,
+					Code: "dnsConfig-searches-count",
+					Msg:  "dnsConfig.searches exceeds 6 entries",
+				})
+			}
+			for _, search := range searchVal.Content {
+				if !isStringScalar(search) {
+					*errs = append(*errs, ValidationError{
+						Line: search.Line,
+						Code: "search-type",
+						Msg:  stringTypeMsg("search", search),
+					})
+				}
+			}
+		}
+	}
+	if optsKey, optsVal := getMapField(node, "options"); optsKey != nil {
+		if optsVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: optsKey.Line,
+				Code: "options-type",
+				Msg:  "options must be array",
+			})
+		} else {
+			for _, opt := range optsVal.Content {
+				if opt.Kind != yaml.MappingNode {
+					*errs = append(*errs, ValidationError{
+						Line: opt.Line,
+						Code: "option-type",
+						Msg:  "option must be object",
+					})
+					continue
+				}
+				nameKey, nameVal := getMapField(opt, "name")
+				if nameKey == nil {
+					*errs = append(*errs, ValidationError{Code: "name-required", Msg: "name is required"})
+				} else if !isStringScalar(nameVal) {
+					*errs = append(*errs, ValidationError{
+						Line: nameKey.Line,
+						Code: "name-type",
+						Msg:  "name must be string",
+					})
+				}
+				if valueKey, valueVal := getMapField(opt, "value"); valueKey != nil && !isStringScalar(valueVal) {
+					*errs = append(*errs, ValidationError{
+						Line: valueKey.Line,
+						Code: "value-type",
+						Msg:  stringTypeMsg("value", valueVal),
+					})
+				}
+			}
+		}
+	}
+}
+
+// validateContainer проверяет контейнер из containers или, при isInit, из
+// initContainers. Для init-контейнеров resources необязательны, а пробы
+// отмечаются только предупреждением --lint.
+func validateContainer(node *yaml.Node, opts Options, isInit bool, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateContainer", errs)()
+	nameKey, nameVal := getMapField(node, "name")
+	if nameKey == nil {
+		*errs = append(*errs, ValidationError{Code: "name-required", Msg: "name is required"})
+	} else {
+		if !isStringScalar(nameVal) {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-type",
+				Msg:  "name must be string",
+			})
+		} else if nameVal.Value == "" {
+			// ПОЛЕ ЕСТЬ, НО ПУСТОЕ -> "name is required"
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-required",
+				Msg:  "name is required",
+			})
+		} else if strings.TrimSpace(nameVal.Value) == "" {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-blank",
+				Msg:  "name must not be blank",
+			})
+		} else {
+			if !snakeCaseRe.MatchString(nameVal.Value) {
+				*errs = append(*errs, ValidationError{
+					Line: nameKey.Line,
+					Code: "name-format",
+					Msg:  fmt.Sprintf("name has invalid format '%s'", nameVal.Value),
+				})
+			}
+			if opts.ReservedNames[nameVal.Value] {
+				*errs = append(*errs, ValidationError{
+					Line: nameKey.Line,
+					Code: "name-reserved",
+					Msg:  fmt.Sprintf("container name '%s' is reserved", nameVal.Value),
+				})
+			}
+		}
+	}
+	imageKey, imageVal := getMapField(node, "image")
+	if imageKey == nil {
+		*errs = append(*errs, ValidationError{Code: "image-required", Msg: "image is required"})
+	} else {
+		if !isStringScalar(imageVal) {
+			*errs = append(*errs, ValidationError{
+				Line: imageKey.Line,
+				Code: "image-type",
+				Msg:  "image must be string",
+			})
+		} else if imageVal.Value == "" {
+			*errs = append(*errs, ValidationError{
+				Line: imageKey.Line,
+				Code: "image-empty",
+				Msg:  "image must not be empty",
+			})
+		} else if strings.IndexFunc(imageVal.Value, unicode.IsSpace) >= 0 {
+			// пробел почти всегда означает ошибку шаблонизации или кавычек
+			*errs = append(*errs, ValidationError{
+				Line: imageKey.Line,
+				Code: "image-whitespace",
+				Msg:  "image must not contain whitespace",
+			})
+		} else if !isValidImage(imageVal.Value, opts) {
+			*errs = append(*errs, ValidationError{
+				Line: imageKey.Line,
+				Code: "image-format",
+				Msg:  fmt.Sprintf("image has invalid format '%s'", imageVal.Value),
+			})
+		} else {
+			if host, _, _ := imageRegistry(imageVal.Value); opts.RegistryPolicies[host] == PolicyDigestOnly && !strings.Contains(imageVal.Value, "@") {
+				*errs = append(*errs, ValidationError{
+					Line: imageKey.Line,
+					Code: "image-digest-required",
+					Msg:  fmt.Sprintf("image from '%s' must be pinned by digest", host),
+				})
+			}
+			if tag, _ := imageTag(imageVal.Value); opts.NoLatest && tag == "latest" {
+				*errs = append(*errs, ValidationError{
+					Line: imageKey.Line,
+					Code: "image-latest",
+					Msg:  "image must not use ':latest' tag",
+				})
+			}
+			if tag, ok := imageTag(imageVal.Value); opts.SemverTags && ok && !semverTagRe.MatchString(tag) {
+				*errs = append(*errs, ValidationError{
+					Line: imageKey.Line,
+					Code: "image-semver",
+					Msg:  fmt.Sprintf("image tag '%s' is not semver", tag),
+				})
+			}
+			if opts.CheckPlatformComment && !hasPlatformComment(imageKey, imageVal) {
+				*errs = append(*errs, ValidationError{
+					Line: imageKey.Line,
+					Code: "image-platform-comment",
+					Msg:  "image missing or invalid platform comment",
+				})
+			}
+			if repo := imageRepository(imageVal.Value); opts.ImageAllowlist != nil && !opts.ImageAllowlist[repo] {
+				*errs = append(*errs, ValidationError{
+					Line: imageKey.Line,
+					Code: "image-not-allowlisted",
+					Msg:  fmt.Sprintf("image repository '%s' is not allowlisted", repo),
+				})
+			}
+		}
+	}
+	if wdKey, wdVal := getMapField(node, "workingDir"); wdKey != nil {
+		if !isStringScalar(wdVal) {
+			*errs = append(*errs, ValidationError{
+				Line: wdKey.Line,
+				Code: "workingDir-type",
+				Msg:  stringTypeMsg("workingDir", wdVal),
+			})
+		} else if !isAbsolutePath(wdVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: wdKey.Line,
+				Code: "workingDir-format",
+				Msg:  fmt.Sprintf("workingDir has invalid format '%s'", wdVal.Value),
+			})
+		}
+	}
+	if tmpKey, tmpVal := getMapField(node, "terminationMessagePath"); tmpKey != nil {
+		if !isStringScalar(tmpVal) {
+			*errs = append(*errs, ValidationError{
+				Line: tmpKey.Line,
+				Code: "terminationMessagePath-type",
+				Msg:  stringTypeMsg("terminationMessagePath", tmpVal),
+			})
+		} else if !isAbsolutePath(tmpVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: tmpKey.Line,
+				Code: "terminationMessagePath-format",
+				Msg:  fmt.Sprintf("terminationMessagePath has invalid format '%s'", tmpVal.Value),
+			})
+		}
+	}
+	if tmpolKey, tmpolVal := getMapField(node, "terminationMessagePolicy"); tmpolKey != nil {
+		if !isStringScalar(tmpolVal) {
+			*errs = append(*errs, ValidationError{
+				Line: tmpolKey.Line,
+				Code: "terminationMessagePolicy-type",
+				Msg:  stringTypeMsg("terminationMessagePolicy", tmpolVal),
+			})
+		} else if !slices.Contains(terminationMessagePolicies, tmpolVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: tmpolKey.Line,
+				Code: "terminationMessagePolicy-value",
+				Msg:  fmt.Sprintf("terminationMessagePolicy has unsupported value '%s'", tmpolVal.Value),
+			})
+		}
+	}
+	if vmKey, vmVal := getMapField(node, "volumeMounts"); vmKey != nil {
+		if vmVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: vmKey.Line,
+				Code: "volumeMounts-type",
+				Msg:  "volumeMounts must be array",
+			})
+		} else {
+			validateVolumeMounts(vmVal, errs)
+		}
+	}
+	validateContainerArgs(node, opts, errs)
+	if scKey, scVal := getOptionalField(node, "securityContext"); scKey != nil {
+		if scVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: scKey.Line,
+				Code: "securityContext-type",
+				Msg:  "securityContext must be object",
+			})
+		} else {
+			validateSecurityContext(scVal, errs)
+		}
+	}
+	if efKey, efVal := getOptionalField(node, "envFrom"); efKey != nil {
+		if efVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: efKey.Line,
+				Code: "envFrom-type",
+				Msg:  "envFrom must be array",
+			})
+		} else {
+			validateEnvFrom(efVal, errs)
+		}
+	}
+	if envKey, envVal := getOptionalField(node, "env"); envKey != nil {
+		if envVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: envKey.Line,
+				Code: "env-type",
+				Msg:  "env must be array",
+			})
+		} else {
+			validateEnv(envVal, errs)
+		}
+	}
+	for _, field := range []string{"stdin", "stdinOnce", "tty"} {
+		if key, val := getMapField(node, field); key != nil && !isBoolScalar(val) {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-type",
+				Msg:  fmt.Sprintf("%s must be bool", field),
+			})
+		}
+	}
+	if _, ttyVal := getMapField(node, "tty"); isTrueScalar(ttyVal) {
+		if _, stdinVal := getMapField(node, "stdin"); !isTrueScalar(stdinVal) {
+			*errs = append(*errs, ValidationError{
+				Line:     node.Line,
+				Code:     "tty-without-stdin",
+				Msg:      "tty requires stdin",
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	if portsKey, portsVal := getOptionalField(node, "ports"); portsKey != nil {
+		if portsVal.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: portsKey.Line,
+				Code: "ports-type",
+				Msg:  "ports must be array",
+			})
+		} else {
+			seen := make(map[string]bool)
+			seenNames := make(map[string]bool)
+			for _, p := range portsVal.Content {
+				if p.Kind != yaml.MappingNode {
+					*errs = append(*errs, ValidationError{
+						Line: p.Line,
+						Code: "port-type",
+						Msg:  "port must be object",
+					})
+					continue
+				}
+				validateContainerPort(p, errs)
+				if nameKey, nameVal := getMapField(p, "name"); isStringScalar(nameVal) {
+					if seenNames[nameVal.Value] {
+						*errs = append(*errs, ValidationError{
+							Line: nameKey.Line,
+							Code: "port-name-duplicate",
+							Msg:  fmt.Sprintf("duplicate port name '%s'", nameVal.Value),
+						})
+					}
+					seenNames[nameVal.Value] = true
+				}
+				// Kubernetes считает уникальной пару containerPort/protocol,
+				// поэтому один номер с TCP и UDP допустим.
+				if key, ok := portProtocolKey(p); ok {
+					if seen[key] {
+						*errs = append(*errs, ValidationError{
+							Line: p.Line,
+							Code: "containerPort-duplicate",
+							Msg:  fmt.Sprintf("duplicate containerPort %s", key),
+						})
+					}
+					seen[key] = true
+				}
+			}
+		}
+	}
+	for _, field := range []string{"readinessProbe", "livenessProbe", "startupProbe"} {
+		probeKey, probeVal := getOptionalField(node, field)
+		if probeKey == nil {
+			continue
+		}
+		if isInit {
+			if opts.Lint {
+				*errs = append(*errs, ValidationError{
+					Line:     probeKey.Line,
+					Code:     "initContainer-probe",
+					Msg:      "probes are not allowed on init containers",
+					Severity: SeverityWarning,
+				})
+			}
+			continue
+		}
+		if probeVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: probeKey.Line,
+				Code: field + "-type",
+				Msg:  fmt.Sprintf("%s must be object", field),
+			})
+		} else {
+			validateProbe(probeKey, probeVal, opts, containerPorts(node), errs)
+		}
+	}
+	if opts.Lint && !isInit {
+		lintStartupWindow(node, errs)
+	}
+	if lcKey, lcVal := getOptionalField(node, "lifecycle"); lcKey != nil {
+		if lcVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: lcKey.Line,
+				Code: "lifecycle-type",
+				Msg:  "lifecycle must be object",
+			})
+		} else {
+			validateLifecycle(lcVal, opts, errs)
+		}
+	}
+	resKey, resVal := getMapField(node, "resources")
+	if resKey == nil {
+		if !isInit {
+			*errs = append(*errs, ValidationError{Code: "resources-required", Msg: "resources is required"})
+		} else if opts.RequireLimits {
+			*errs = append(*errs, ValidationError{Code: "limits-required", Msg: "limits is required"})
+		}
+	} else {
+		if resVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: resKey.Line,
+				Code: "resources-type",
+				Msg:  "resources must be object",
+			})
+		} else {
+			validateResources(resVal, opts, errs)
+			if opts.Lint {
+				validateResourceSymmetry(resKey, resVal, errs)
+			}
+			if opts.QoS == "guaranteed" {
+				validateGuaranteedQoS(resKey, resVal, nameVal, errs)
+			}
+		}
+	}
+}
+
+// validateContainerArgs проверяет command и args контейнера: это массивы
+// строк. С --lint-shell предупреждает о метасимволах оболочки в
+// exec-форме, кроме скрипта сразу после "-c".
+func validateContainerArgs(node *yaml.Node, opts Options, errs *[]ValidationError) {
+	var entries []*yaml.Node
+	for _, field := range []string{"command", "args"} {
+		key, val := getOptionalField(node, field)
+		if key == nil {
+			continue
+		}
+		if val.Kind != yaml.SequenceNode {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-type",
+				Msg:  fmt.Sprintf("%s must be array", field),
+			})
+			continue
+		}
+		for _, arg := range val.Content {
+			if !isStringScalar(arg) {
+				*errs = append(*errs, ValidationError{
+					Line: arg.Line,
+					Code: field + "-entry-type",
+					Msg:  fmt.Sprintf("%s entry must be string", field),
+				})
+				continue
+			}
+			entries = append(entries, arg)
+		}
+	}
+	if !opts.LintShell {
+		return
+	}
+	for i, arg := range entries {
+		if i > 0 && entries[i-1].Value == "-c" {
+			continue
+		}
+		if hasShellMetachars(arg.Value) {
+			*errs = append(*errs, ValidationError{
+				Line:     arg.Line,
+				Code:     "args-shell",
+				Msg:      fmt.Sprintf("arg '%s' contains shell metacharacters", arg.Value),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+}
+
+func hasShellMetachars(s string) bool {
+	return strings.ContainsAny(s, ";|`") || strings.Contains(s, "&&")
+}
+
+// validateEnv проверяет переменные окружения контейнера. При повторе имени
+// Kubernetes молча берёт последнее значение, поэтому повтор — ошибка.
+func validateEnv(node *yaml.Node, errs *[]ValidationError) {
+	seen := make(map[string]bool)
+	for _, ev := range node.Content {
+		if ev.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: ev.Line,
+				Code: "env-entry-type",
+				Msg:  "env entry must be object",
+			})
+			continue
+		}
+		nameKey, nameVal := getMapField(ev, "name")
+		if nameKey == nil {
+			*errs = append(*errs, ValidationError{Code: "name-required", Msg: "name is required"})
+		} else if !isStringScalar(nameVal) {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-type",
+				Msg:  "name must be string",
+			})
+		} else if nameVal.Value == "" {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-empty",
+				Msg:  "name must not be empty",
+			})
+		} else if seen[nameVal.Value] {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "env-duplicate",
+				Msg:  fmt.Sprintf("duplicate env variable '%s'", nameVal.Value),
+			})
+		} else {
+			seen[nameVal.Value] = true
+		}
+		if valueKey, valueVal := getMapField(ev, "value"); valueKey != nil && !isStringScalar(valueVal) {
+			*errs = append(*errs, ValidationError{
+				Line: valueKey.Line,
+				Code: "value-type",
+				Msg:  stringTypeMsg("value", valueVal),
+			})
+		}
+		if vfKey, vfVal := getMapField(ev, "valueFrom"); vfKey != nil {
+			if vfVal.Kind != yaml.MappingNode {
+				*errs = append(*errs, ValidationError{
+					Line: vfKey.Line,
+					Code: "valueFrom-type",
+					Msg:  "valueFrom must be object",
+				})
+				continue
+			}
+			for _, ref := range []string{"configMapKeyRef", "secretKeyRef"} {
+				if refKey, refVal := getMapField(vfVal, ref); refKey != nil {
+					validateKeyRef(refKey, refVal, errs)
+				}
+			}
+		}
+	}
+}
+
+// validateEnvFrom проверяет источники envFrom: ровно одну ссылку на
+// ConfigMap или Secret и необязательный строковый prefix.
+func validateEnvFrom(node *yaml.Node, errs *[]ValidationError) {
+	for _, src := range node.Content {
+		if src.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: src.Line,
+				Code: "envFrom-entry-type",
+				Msg:  "envFrom entry must be object",
+			})
+			continue
+		}
+		refs := 0
+		for _, ref := range []string{"configMapRef", "secretRef"} {
+			refKey, refVal := getMapField(src, ref)
+			if refKey == nil {
+				continue
+			}
+			refs++
+			if refVal.Kind != yaml.MappingNode {
+				*errs = append(*errs, ValidationError{
+					Line: refKey.Line,
+					Code: ref + "-type",
+					Msg:  fmt.Sprintf("%s must be object", ref),
+				})
+				continue
+			}
+			if nameKey, nameVal := getMapField(refVal, "name"); nameKey != nil && !isStringScalar(nameVal) {
+				*errs = append(*errs, ValidationError{
+					Line: nameKey.Line,
+					Code: "name-type",
+					Msg:  "name must be string",
+				})
+			}
+		}
+		if refs != 1 {
+			*errs = append(*errs, ValidationError{
+				Line: src.Line,
+				Code: "envFrom-source",
+				Msg:  "envFrom entry must have exactly one of configMapRef or secretRef",
+			})
+		}
+		if prefixKey, prefixVal := getMapField(src, "prefix"); prefixKey != nil && !isStringScalar(prefixVal) {
+			*errs = append(*errs, ValidationError{
+				Line: prefixKey.Line,
+				Code: "prefix-type",
+				Msg:  stringTypeMsg("prefix", prefixVal),
+			})
+		}
+	}
+}
+
+// validateKeyRef проверяет ссылку на ключ ConfigMap или Secret.
+func validateKeyRef(refKey, node *yaml.Node, errs *[]ValidationError) {
+	ref := refKey.Value
+	if node.Kind != yaml.MappingNode {
+		*errs = append(*errs, ValidationError{
+			Line: refKey.Line,
+			Code: ref + "-type",
+			Msg:  fmt.Sprintf("%s must be object", ref),
+		})
+		return
+	}
+	keyKey, keyVal := getMapField(node, "key")
+	if keyKey == nil {
+		*errs = append(*errs, ValidationError{
+			Line: refKey.Line,
+			Code: "key-required",
+			Msg:  fmt.Sprintf("%s key is required", ref),
+		})
+	} else if !isStringScalar(keyVal) {
+		*errs = append(*errs, ValidationError{
+			Line: keyKey.Line,
+			Code: "key-type",
+			Msg:  stringTypeMsg("key", keyVal),
+		})
+	} else if !configKeyRe.MatchString(keyVal.Value) {
+		*errs = append(*errs, ValidationError{
+			Line: keyKey.Line,
+			Code: "key-format",
+			Msg:  fmt.Sprintf("%s key '%s' is invalid", ref, keyVal.Value),
+		})
+	}
+	if nameKey, nameVal := getMapField(node, "name"); nameKey != nil {
+		if !isStringScalar(nameVal) {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-type",
+				Msg:  "name must be string",
+			})
+		} else if !isDNSSubdomain(nameVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-format",
+				Msg:  dnsFormatMsg("name", nameVal.Value),
+			})
+		}
+	}
+	if optKey, optVal := getMapField(node, "optional"); optKey != nil && !isBoolScalar(optVal) {
+		*errs = append(*errs, ValidationError{
+			Line: optKey.Line,
+			Code: "optional-type",
+			Msg:  "optional must be bool",
+		})
+	}
+}
+
+func validateVolumeMounts(node *yaml.Node, errs *[]ValidationError) {
+	type mount struct {
+		path string
+		line int
+	}
+	var mounts []mount
+	for _, vm := range node.Content {
+		if vm.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: vm.Line,
+				Code: "volumeMount-type",
+				Msg:  "volumeMount must be object",
+			})
+			continue
+		}
+		nameKey, nameVal := getMapField(vm, "name")
+		if nameKey == nil {
+			*errs = append(*errs, ValidationError{Code: "name-required", Msg: "name is required"})
+		} else if !isStringScalar(nameVal) {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-type",
+				Msg:  "name must be string",
+			})
+		} else if nameVal.Value == "" {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-empty",
+				Msg:  "name must not be empty",
+			})
+		} else if strings.TrimSpace(nameVal.Value) == "" {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-blank",
+				Msg:  "name must not be blank",
+			})
+		}
+		mpKey, mpVal := getMapField(vm, "mountPath")
+		if mpKey == nil {
+			*errs = append(*errs, ValidationError{Code: "mountPath-required", Msg: "mountPath is required"})
+		} else if !isStringScalar(mpVal) {
+			*errs = append(*errs, ValidationError{
+				Line: mpKey.Line,
+				Code: "mountPath-type",
+				Msg:  stringTypeMsg("mountPath", mpVal),
+			})
+		} else if mpVal.Value == "" {
+			*errs = append(*errs, ValidationError{
+				Line: mpKey.Line,
+				Code: "mountPath-empty",
+				Msg:  "mountPath must not be empty",
+			})
+		} else if !isAbsolutePath(mpVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: mpKey.Line,
+				Code: "mountPath-format",
+				Msg:  fmt.Sprintf("mountPath has invalid format '%s'", mpVal.Value),
+			})
+		} else {
+			mounts = append(mounts, mount{path: normalizeMountPath(mpVal.Value), line: mpKey.Line})
+		}
+		spKey, spVal := getMapField(vm, "subPath")
+		if spKey != nil {
+			if !isStringScalar(spVal) {
+				*errs = append(*errs, ValidationError{
+					Line: spKey.Line,
+					Code: "subPath-type",
+					Msg:  stringTypeMsg("subPath", spVal),
+				})
+			} else if isAbsolutePath(spVal.Value) {
+				*errs = append(*errs, ValidationError{
+					Line: spKey.Line,
+					Code: "subPath-absolute",
+					Msg:  "subPath must not be absolute",
+				})
+			}
+		}
+		if speKey, speVal := getMapField(vm, "subPathExpr"); speKey != nil {
+			if !isStringScalar(speVal) {
+				*errs = append(*errs, ValidationError{
+					Line: speKey.Line,
+					Code: "subPathExpr-type",
+					Msg:  stringTypeMsg("subPathExpr", speVal),
+				})
+			}
+			if spKey != nil {
+				*errs = append(*errs, ValidationError{
+					Line: speKey.Line,
+					Code: "subPath-exclusive",
+					Msg:  "subPath and subPathExpr are mutually exclusive",
+				})
+			}
+		}
+		if roKey, roVal := getMapField(vm, "readOnly"); roKey != nil && !isBoolScalar(roVal) {
+			*errs = append(*errs, ValidationError{
+				Line: roKey.Line,
+				Code: "readOnly-type",
+				Msg:  "readOnly must be bool",
+			})
+		}
+	}
+	seen := make(map[string]bool)
+	var distinct []string
+	for _, m := range mounts {
+		if seen[m.path] {
+			*errs = append(*errs, ValidationError{
+				Line: m.line,
+				Code: "mountPath-duplicate",
+				Msg:  fmt.Sprintf("duplicate mountPath '%s'", m.path),
+			})
+			continue
+		}
+		for _, prev := range distinct {
+			if isSubPath(m.path, prev) || isSubPath(prev, m.path) {
+				*errs = append(*errs, ValidationError{
+					Line:     m.line,
+					Code:     "mountPath-overlap",
+					Msg:      fmt.Sprintf("mountPath '%s' overlaps '%s'", m.path, prev),
+					Severity: SeverityWarning,
+				})
+			}
+		}
+		seen[m.path] = true
+		distinct = append(distinct, m.path)
+	}
+}
+
+func normalizeMountPath(p string) string {
+	if trimmed := strings.TrimRight(p, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
+
+// isSubPath сообщает, вложен ли путь p в каталог dir.
+func isSubPath(p, dir string) bool {
+	if dir == "/" {
+		return p != "/"
+	}
+	return strings.HasPrefix(p, dir+"/")
+}
+
+// portProtocolKey возвращает пару containerPort/protocol; протокол по умолчанию TCP.
+func portProtocolKey(port *yaml.Node) (string, bool) {
+	_, cpVal := getMapField(port, "containerPort")
+	if cpVal == nil || !isIntScalar(cpVal) {
+		return "", false
+	}
+	proto := "TCP"
+	if _, protoVal := getMapField(port, "protocol"); protoVal != nil && isStringScalar(protoVal) {
+		proto = protoVal.Value
+	}
+	return cpVal.Value + "/" + proto, true
+}
+
+func validateContainerPort(node *yaml.Node, errs *[]ValidationError) {
+	cpKey, cpVal := getMapField(node, "containerPort")
+	if cpKey == nil {
+		*errs = append(*errs, ValidationError{Code: "containerPort-required", Msg: "containerPort is required"})
+	} else {
+		validatePortValue(cpKey, cpVal, false, errs)
+	}
+	if nameKey, nameVal := getMapField(node, "name"); nameKey != nil {
+		if !isStringScalar(nameVal) {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-type",
+				Msg:  "name must be string",
+			})
+		} else if !isPortName(nameVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: nameKey.Line,
+				Code: "name-format",
+				Msg:  fmt.Sprintf("name has invalid format '%s'", nameVal.Value),
+			})
+		}
+	}
+	if protoKey, protoVal := getMapField(node, "protocol"); protoKey != nil {
+		if !isStringScalar(protoVal) {
+			*errs = append(*errs, ValidationError{
+				Line: protoKey.Line,
+				Code: "protocol-type",
+				Msg:  "protocol must be string",
+			})
+		} else if upper := strings.ToUpper(protoVal.Value); !isProtocol(protoVal.Value) && isProtocol(upper) {
+			*errs = append(*errs, ValidationError{
+				Line: protoKey.Line,
+				Code: "protocol-value",
+				Msg:  fmt.Sprintf("protocol has unsupported value '%s' (did you mean '%s'?)", protoVal.Value, upper),
+			})
+		} else if !isProtocol(protoVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: protoKey.Line,
+				Code: "protocol-value",
+				Msg:  fmt.Sprintf("protocol has unsupported value '%s'", protoVal.Value),
+			})
+		}
+	}
+}
+func validateProbe(probeKey, node *yaml.Node, opts Options, declared *portSet, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateProbe", errs)()
+	validateHandler(node, opts, declared, errs)
+	for _, field := range []string{"initialDelaySeconds", "periodSeconds", "timeoutSeconds", "failureThreshold", "successThreshold"} {
+		key, val := getMapField(node, field)
+		if key == nil {
+			continue
+		}
+		if !isIntScalar(val) {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-type",
+				Msg:  fmt.Sprintf("%s must be int", field),
+			})
+		} else if min, ok := probeMinimums[field]; ok {
+			if n, err := strconv.Atoi(val.Value); err == nil && n < min {
+				*errs = append(*errs, ValidationError{
+					Line: key.Line,
+					Code: field + "-range",
+					Msg:  fmt.Sprintf("%s must be >= %d", field, min),
+				})
+			}
+		}
+	}
+	if timeoutKey, _ := getMapField(node, "timeoutSeconds"); opts.Lint && timeoutKey != nil {
+		timeout, period := probeInt(node, "timeoutSeconds"), probeInt(node, "periodSeconds")
+		if timeout > period {
+			*errs = append(*errs, ValidationError{
+				Line:     probeKey.Line,
+				Code:     "timeoutSeconds-exceeds-period",
+				Msg:      fmt.Sprintf("timeoutSeconds (%d) should not exceed periodSeconds (%d)", timeout, period),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+}
+
+// Значения полей пробы по умолчанию в Kubernetes.
+var probeDefaults = map[string]int{
+	"initialDelaySeconds": 0,
+	"periodSeconds":       10,
+	"timeoutSeconds":      1,
+	"failureThreshold":    3,
+	"successThreshold":    1,
+}
+
+// Минимальные допустимые значения полей пробы, как в валидации Kubernetes.
+var probeMinimums = map[string]int{
+	"initialDelaySeconds": 0,
+	"periodSeconds":       1,
+	"failureThreshold":    1,
+}
+
+// probeInt возвращает числовое поле пробы или значение по умолчанию, если
+// поле не задано или не является числом.
+func probeInt(probe *yaml.Node, field string) int {
+	if _, v := getMapField(probe, field); isIntScalar(v) {
+		if n, err := strconv.Atoi(v.Value); err == nil {
+			return n
+		}
+	}
+	return probeDefaults[field]
+}
+
+// lintStartupWindow предупреждает, когда startupProbe успевает исчерпать
+// failureThreshold*periodSeconds раньше, чем начнётся livenessProbe.
+func lintStartupWindow(container *yaml.Node, errs *[]ValidationError) {
+	startupKey, startup := getOptionalField(container, "startupProbe")
+	_, liveness := getOptionalField(container, "livenessProbe")
+	if startupKey == nil || liveness == nil || startup.Kind != yaml.MappingNode || liveness.Kind != yaml.MappingNode {
+		return
+	}
+	window := probeInt(startup, "failureThreshold") * probeInt(startup, "periodSeconds")
+	if window < probeInt(liveness, "initialDelaySeconds") {
+		*errs = append(*errs, ValidationError{
+			Line:     startupKey.Line,
+			Code:     "startupProbe-window",
+			Msg:      "startup probe window is shorter than liveness initialDelaySeconds",
+			Severity: SeverityWarning,
+		})
+	}
+}
+
+// validateHandler проверяет обработчик пробы или хука: exec, httpGet или tcpSocket.
+// Порты сверяются с объявленными только при declared != nil.
+func validateHandler(node *yaml.Node, opts Options, declared *portSet, errs *[]ValidationError) {
+	httpKey, httpVal := getMapField(node, "httpGet")
+	if httpKey == nil {
+		if execKey, execVal := getMapField(node, "exec"); execKey != nil {
+			if execVal.Kind != yaml.MappingNode {
+				*errs = append(*errs, ValidationError{
+					Line: execKey.Line,
+					Code: "exec-type",
+					Msg:  "exec must be object",
+				})
+				return
+			}
+			validateCommand(execVal, errs)
+			return
+		}
+		if tcpKey, tcpVal := getMapField(node, "tcpSocket"); tcpKey != nil {
+			if tcpVal.Kind != yaml.MappingNode {
+				*errs = append(*errs, ValidationError{
+					Line: tcpKey.Line,
+					Code: "tcpSocket-type",
+					Msg:  "tcpSocket must be object",
+				})
+				return
+			}
+			validateProbePort(tcpVal, opts, declared, errs)
+			return
+		}
+		*errs = append(*errs, ValidationError{Code: "httpGet-required", Msg: "httpGet is required"})
+		return
+	}
+	if httpVal.Kind != yaml.MappingNode {
+		*errs = append(*errs, ValidationError{
+			Line: httpKey.Line,
+			Code: "httpGet-type",
+			Msg:  "httpGet must be object",
+		})
+		return
+	}
+	pathKey, pathVal := getMapField(httpVal, "path")
+	if pathKey == nil {
+		*errs = append(*errs, ValidationError{Code: "path-required", Msg: "path is required"})
+	} else {
+		if !isStringScalar(pathVal) {
+			*errs = append(*errs, ValidationError{
+				Line: pathKey.Line,
+				Code: "path-type",
+				Msg:  "path must be string",
+			})
+		} else if pathVal.Value == "" {
+			*errs = append(*errs, ValidationError{
+				Line: pathKey.Line,
+				Code: "path-empty",
+				Msg:  "path must not be empty",
+			})
+		} else if !isAbsolutePath(pathVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: pathKey.Line,
+				Code: "path-format",
+				Msg:  fmt.Sprintf("path has invalid format '%s'", pathVal.Value),
+			})
+		}
+	}
+	validateProbePort(httpVal, opts, declared, errs)
+}
+
+func validateCommand(exec *yaml.Node, errs *[]ValidationError) {
+	cmdKey, cmdVal := getMapField(exec, "command")
+	if cmdKey == nil {
+		*errs = append(*errs, ValidationError{Code: "command-required", Msg: "command is required"})
+		return
+	}
+	if cmdVal.Kind != yaml.SequenceNode {
+		*errs = append(*errs, ValidationError{
+			Line: cmdKey.Line,
+			Code: "command-type",
+			Msg:  "command must be array",
+		})
+		return
+	}
+	for _, arg := range cmdVal.Content {
+		if !isStringScalar(arg) {
+			*errs = append(*errs, ValidationError{
+				Line: arg.Line,
+				Code: "command-entry-type",
+				Msg:  "command entry must be string",
+			})
+		}
+	}
+}
+
+func validateLifecycle(node *yaml.Node, opts Options, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateLifecycle", errs)()
+	for _, hook := range []string{"postStart", "preStop"} {
+		hookKey, hookVal := getMapField(node, hook)
+		if hookKey == nil {
+			continue
+		}
+		if hookVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: hookKey.Line,
+				Code: hook + "-type",
+				Msg:  fmt.Sprintf("%s must be object", hook),
+			})
+			continue
+		}
+		validateHandler(hookVal, opts, nil, errs)
+		_, execVal := getMapField(hookVal, "exec")
+		if cmdKey, cmdVal := getMapField(execVal, "command"); cmdKey != nil && cmdVal.Kind == yaml.SequenceNode && len(cmdVal.Content) == 0 {
+			*errs = append(*errs, ValidationError{
+				Line: cmdKey.Line,
+				Code: "lifecycle-command-empty",
+				Msg:  "lifecycle hook command must not be empty",
+			})
+		}
+	}
+}
+
+func validateProbePort(handler *yaml.Node, opts Options, declared *portSet, errs *[]ValidationError) {
+	portKey, portVal := getMapField(handler, "port")
+	if portKey == nil {
+		*errs = append(*errs, ValidationError{Code: "port-required", Msg: "port is required"})
+		return
+	}
+	if !validatePortValue(portKey, portVal, true, errs) || declared == nil {
+		return
+	}
+	if isStringScalar(portVal) && declared.ambiguous[portVal.Value] {
+		*errs = append(*errs, ValidationError{
+			Line: portKey.Line,
+			Code: "probe-port-name-ambiguous",
+			Msg:  fmt.Sprintf("probe references ambiguous port name '%s'", portVal.Value),
+		})
+		return
+	}
+	if !opts.Lint {
+		return
+	}
+	if isIntScalar(portVal) {
+		port, _ := strconv.Atoi(portVal.Value)
+		if !declared.numbers[port] {
+			*errs = append(*errs, ValidationError{
+				Line:     portKey.Line,
+				Code:     "probe-port-undeclared",
+				Msg:      fmt.Sprintf("probe port %d is not a declared containerPort", port),
+				Severity: SeverityWarning,
+			})
+		}
+	} else if !declared.hasName(portVal.Value) {
+		*errs = append(*errs, ValidationError{
+			Line:     portKey.Line,
+			Code:     "probe-port-name-undeclared",
+			Msg:      fmt.Sprintf("probe port name '%s' is not a declared port name", portVal.Value),
+			Severity: SeverityWarning,
+		})
+	}
+}
+
+// validatePortValue проверяет значение порта: число 1-65535 или, при
+// allowNamed, имя порта IANA. Коды и сообщения строятся из имени ключа.
+// Возвращает true, если значение корректно.
+func validatePortValue(key, node *yaml.Node, allowNamed bool, errs *[]ValidationError) bool {
+	field := key.Value
+	switch {
+	case isQuotedNumber(node):
+		*errs = append(*errs, ValidationError{
+			Line: key.Line,
+			Code: field + "-type",
+			Msg:  fmt.Sprintf("%s must be int, remove the quotes around '%s'", field, node.Value),
+		})
+		return false
+	case isIntScalar(node):
+		port, _ := strconv.Atoi(node.Value)
+		if port <= 0 || port >= 65536 {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-range",
+				Msg:  fmt.Sprintf("%s value out of range", field),
+			})
+			return false
+		}
+	case allowNamed && isStringScalar(node):
+		if !isPortName(node.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: key.Line,
+				Code: field + "-format",
+				Msg:  fmt.Sprintf("%s has invalid format '%s'", field, node.Value),
+			})
+			return false
+		}
+	default:
+		msg := fmt.Sprintf("%s must be int", field)
+		if allowNamed {
+			msg = fmt.Sprintf("%s must be int or string", field)
+		}
+		*errs = append(*errs, ValidationError{
+			Line: key.Line,
+			Code: field + "-type",
+			Msg:  msg,
+		})
+		return false
+	}
+	return true
+}
+
+// portSet описывает порты, объявленные контейнером: номера и имена.
+type portSet struct {
+	numbers map[int]bool
+	names   map[string]int
+	// ambiguous — имена, объявленные у нескольких портов.
+	ambiguous map[string]bool
+}
+
+func (p *portSet) hasName(name string) bool {
+	_, ok := p.names[name]
+	return ok
+}
+
+func containerPorts(node *yaml.Node) *portSet {
+	ports := &portSet{
+		numbers:   make(map[int]bool),
+		names:     make(map[string]int),
+		ambiguous: make(map[string]bool),
+	}
+	_, portsVal := getMapField(node, "ports")
+	if portsVal == nil || portsVal.Kind != yaml.SequenceNode {
+		return ports
+	}
+	for _, p := range portsVal.Content {
+		_, cpVal := getMapField(p, "containerPort")
+		if cpVal == nil || !isIntScalar(cpVal) {
+			continue
+		}
+		port, err := strconv.Atoi(cpVal.Value)
+		if err != nil {
+			continue
+		}
+		ports.numbers[port] = true
+		if _, nameVal := getMapField(p, "name"); nameVal != nil && isStringScalar(nameVal) {
+			if ports.hasName(nameVal.Value) {
+				ports.ambiguous[nameVal.Value] = true
+			}
+			ports.names[nameVal.Value] = port
+		}
+	}
+	return ports
+}
+
+func validateResources(node *yaml.Node, opts Options, errs *[]ValidationError) {
+	defer traceValidator(opts, "validateResources", errs)()
+	// limits (опционально, обязательны при --require-limits)
+	limitsKey, limitsVal := getOptionalField(node, "limits")
+	if limitsKey == nil {
+		if opts.RequireLimits {
+			*errs = append(*errs, ValidationError{Code: "limits-required", Msg: "limits is required"})
+		}
+	} else {
+		if limitsVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: limitsKey.Line,
+				Code: "limits-type",
+				Msg:  "limits must be object",
+			})
+		} else {
+			validateResourceMap(limitsVal, opts, errs)
+			if opts.RequireLimits {
+				for _, res := range []string{"cpu", "memory"} {
+					if k, _ := getMapField(limitsVal, res); k == nil {
+						*errs = append(*errs, ValidationError{Code: res + "-limit-required", Msg: res + " limit is required"})
+					}
+				}
+			}
+		}
+	}
+	if reqKey, reqVal := getOptionalField(node, "requests"); reqKey != nil {
+		if reqVal.Kind != yaml.MappingNode {
+			*errs = append(*errs, ValidationError{
+				Line: reqKey.Line,
+				Code: "requests-type",
+				Msg:  "requests must be object",
+			})
+		} else {
+			validateResourceMap(reqVal, opts, errs)
+		}
+	}
+}
+
+// validateGuaranteedQoS требует, чтобы у контейнера были заданы cpu и memory
+// и для каждого ресурса запрос совпадал с лимитом.
+func validateGuaranteedQoS(key, node, name *yaml.Node, errs *[]ValidationError) {
+	_, limitsVal := getOptionalField(node, "limits")
+	_, reqVal := getOptionalField(node, "requests")
+	resources := []string{"cpu", "memory"}
+	for _, m := range []*yaml.Node{limitsVal, reqVal} {
+		if m == nil || m.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			if res := m.Content[i].Value; !slices.Contains(resources, res) {
+				resources = append(resources, res)
+			}
+		}
+	}
+	containerName := ""
+	if isStringScalar(name) {
+		containerName = name.Value
+	}
+	for _, res := range resources {
+		_, limit := getMapField(limitsVal, res)
+		_, request := getMapField(reqVal, res)
+		var reason string
+		switch {
+		case limit == nil:
+			reason = fmt.Sprintf("%s limit is missing", res)
+		case request == nil:
+			reason = fmt.Sprintf("%s request is missing", res)
+		case !sameQuantity(request, limit):
+			reason = fmt.Sprintf("%s request %s differs from limit %s", res, request.Value, limit.Value)
+		default:
+			continue
+		}
+		*errs = append(*errs, ValidationError{
+			Line: key.Line,
+			Code: "qos-guaranteed",
+			Msg:  fmt.Sprintf("container '%s' is not Guaranteed QoS: %s", containerName, reason),
+		})
+	}
+}
+
+// sameQuantity сравнивает количества ресурса; значения, которые не удаётся
+// разобрать, сравниваются как строки.
+func sameQuantity(a, b *yaml.Node) bool {
+	x, okA := ParseMemory(a.Value)
+	y, okB := ParseMemory(b.Value)
+	if okA && okB {
+		return x == y
+	}
+	return a.Value == b.Value
+}
+
+func validateResourceSymmetry(key, node *yaml.Node, errs *[]ValidationError) {
+	_, limitsVal := getMapField(node, "limits")
+	_, reqVal := getMapField(node, "requests")
+	if limitsVal == nil || reqVal == nil || limitsVal.Kind != yaml.MappingNode || reqVal.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(limitsVal.Content); i += 2 {
+		res := limitsVal.Content[i].Value
+		if k, _ := getMapField(reqVal, res); k == nil {
+			*errs = append(*errs, ValidationError{
+				Line:     key.Line,
+				Code:     "limit-without-request",
+				Msg:      fmt.Sprintf("%s limit set without request", res),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	for i := 0; i+1 < len(reqVal.Content); i += 2 {
+		res := reqVal.Content[i].Value
+		if k, _ := getMapField(limitsVal, res); k == nil {
+			*errs = append(*errs, ValidationError{
+				Line:     key.Line,
+				Code:     "request-without-limit",
+				Msg:      fmt.Sprintf("%s request set without limit", res),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+}
+
+func validateResourceMap(node *yaml.Node, opts Options, errs *[]ValidationError) {
+	if opts.Strict {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if k := node.Content[i]; !isKnownResource(k.Value) {
+				*errs = append(*errs, ValidationError{
+					Line: k.Line,
+					Code: "resource-unknown",
+					Msg:  fmt.Sprintf("unknown resource '%s'", k.Value),
+				})
+			}
+		}
+	}
+	if cpuKey, cpuVal := getMapField(node, "cpu"); cpuKey != nil {
+		if !isIntScalar(cpuVal) {
+			*errs = append(*errs, ValidationError{
+				Line: cpuKey.Line,
+				Code: "cpu-type",
+				Msg:  "cpu must be int",
+			})
+		} else if cpu, err := strconv.ParseInt(cpuVal.Value, 10, 64); err != nil {
+			// YAML помечает 0x10 и 0o17 как !!int, но Kubernetes такие записи не принимает
+			*errs = append(*errs, ValidationError{
+				Line: cpuKey.Line,
+				Code: "cpu-format",
+				Msg:  "cpu must be a decimal integer",
+			})
+		} else if opts.Lint && cpu > opts.maxCPUCores() {
+			*errs = append(*errs, ValidationError{
+				Line:     cpuKey.Line,
+				Code:     "cpu-millicores",
+				Msg:      fmt.Sprintf("cpu value %d looks like millicores; did you mean '%dm'?", cpu, cpu),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if !hugepagesRe.MatchString(k.Value) {
+			continue
+		}
+		if !isStringScalar(v) {
+			*errs = append(*errs, ValidationError{
+				Line: k.Line,
+				Code: "hugepages-type",
+				Msg:  fmt.Sprintf("%s must be string", k.Value),
+			})
+		} else if !memoryRe.MatchString(v.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: k.Line,
+				Code: "hugepages-format",
+				Msg:  "hugepages value has invalid format",
+			})
+		}
+	}
+	if memKey, memVal := getMapField(node, "memory"); memKey != nil {
+		if !isStringScalar(memVal) {
+			*errs = append(*errs, ValidationError{
+				Line: memKey.Line,
+				Code: "memory-type",
+				Msg:  "memory must be string",
+			})
+		} else if decimalMemoryRe.MatchString(memVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: memKey.Line,
+				Code: "memory-decimal",
+				Msg:  fmt.Sprintf("memory must be an integer quantity, got '%s'", memVal.Value),
+			})
+		} else if !memoryRe.MatchString(memVal.Value) {
+			*errs = append(*errs, ValidationError{
+				Line: memKey.Line,
+				Code: "memory-format",
+				Msg:  fmt.Sprintf("memory has invalid format '%s'", memVal.Value),
+			})
+		}
+	}
+}
+
+// isKnownResource сообщает, является ли ключ стандартным или расширенным
+// (domain/name) ресурсом.
+func isKnownResource(name string) bool {
+	switch name {
+	case "cpu", "memory", "ephemeral-storage":
+		return true
+	}
+	return hugepagesRe.MatchString(name) || strings.Contains(name, "/")
+}
+
+var memoryUnits = map[string]int64{
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+}
+
+// ParseMemory переводит количество памяти вида 512Mi или 134217728
+// (байты без суффикса) в байты.
+func ParseMemory(s string) (int64, bool) {
+	m := memoryRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if m[2] == "" {
+		return n, true
+	}
+	return n * memoryUnits[m[2]], true
+}
+
+func formatMemory(bytes int64) string {
+	for _, unit := range []string{"Gi", "Mi", "Ki"} {
+		if size := memoryUnits[unit]; bytes >= size && bytes%size == 0 {
+			return fmt.Sprintf("%d%s", bytes/size, unit)
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+func getMapField(m *yaml.Node, field string) (keyNode, valueNode *yaml.Node) {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k := m.Content[i]
+		v := m.Content[i+1]
+		if k.Value == field {
+			return k, v
+		}
+	}
+	return nil, nil
+}
+
+// getOptionalField работает как getMapField, но считает явный null
+// (например, "labels:" без значения) отсутствующим полем.
+func getOptionalField(m *yaml.Node, field string) (keyNode, valueNode *yaml.Node) {
+	keyNode, valueNode = getMapField(m, field)
+	if isNullScalar(valueNode) {
+		return nil, nil
+	}
+	return keyNode, valueNode
+}
+
+func isNullScalar(n *yaml.Node) bool {
+	return n != nil && n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+func isStringScalar(n *yaml.Node) bool {
+	return n != nil && n.Kind == yaml.ScalarNode && n.Tag == "!!str"
+}
+
+func isBoolScalar(n *yaml.Node) bool {
+	return n != nil && n.Kind == yaml.ScalarNode && n.Tag == "!!bool"
+}
+
+func isTrueScalar(n *yaml.Node) bool {
+	return isBoolScalar(n) && strings.EqualFold(n.Value, "true")
+}
+
+// stringTypeMsg — сообщение для поля, которое должно быть строкой. Для
+// true/false подсказывает взять значение в кавычки. Исходные поля
+// валидатора (apiVersion, kind, name и другие) сохраняют прежнее
+// сообщение "<field> must be string".
+func stringTypeMsg(field string, val *yaml.Node) string {
+	if isBoolScalar(val) {
+		return fmt.Sprintf("%s looks like a YAML boolean; quote it if you meant a string", field)
+	}
+	return fmt.Sprintf("%s must be string", field)
+}
+
+// isQuotedNumber сообщает, что скаляр — число, взятое в кавычки.
+func isQuotedNumber(n *yaml.Node) bool {
+	if !isStringScalar(n) || n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) == 0 {
+		return false
+	}
+	_, err := strconv.Atoi(n.Value)
+	return err == nil
+}
+
+func isIntScalar(n *yaml.Node) bool {
+	return n != nil && n.Kind == yaml.ScalarNode && n.Tag == "!!int"
+}
+
+// dnsFormatMsg — сообщение для имени, не прошедшего проверку DNS-поддомена.
+// Если имя исправляется переводом в нижний регистр, подсказывает вариант.
+func dnsFormatMsg(field, value string) string {
+	if lower := strings.ToLower(value); lower != value && isDNSSubdomain(lower) {
+		return fmt.Sprintf("%s has invalid format '%s' (did you mean '%s'?)", field, value, lower)
+	}
+	return fmt.Sprintf("%s has invalid format '%s'", field, value)
+}
+
+func isDNSSubdomain(s string) bool {
+	return len(s) <= 253 && dnsSubdomainRe.MatchString(s)
+}
+
+func isProtocol(s string) bool {
+	return slices.Contains(protocols, s)
+}
+
+// isPortName проверяет имя порта по правилам IANA_SVC_NAME.
+func isPortName(s string) bool {
+	return len(s) <= 15 && portNameRe.MatchString(s) && !strings.Contains(s, "--") &&
+		strings.ContainsAny(s, "abcdefghijklmnopqrstuvwxyz")
+}
+
+// isQualifiedName проверяет имя вида [prefix/]name, где prefix — DNS-поддомен.
+func isQualifiedName(s string) bool {
+	name := s
+	if slash := strings.Index(s, "/"); slash != -1 {
+		if !isDNSSubdomain(s[:slash]) {
+			return false
+		}
+		name = s[slash+1:]
+	}
+	return len(name) <= 63 && qualifiedNameRe.MatchString(name)
+}
+
+func isAbsolutePath(s string) bool {
+	return strings.HasPrefix(s, "/")
+}
+
+// hasPlatformComment ищет у образа комментарий "# platform: linux/<arch>".
+// yaml.v3 может привязать комментарий строки и к ключу, и к значению.
+func hasPlatformComment(key, val *yaml.Node) bool {
+	return platformCommentRe.MatchString(val.LineComment) || platformCommentRe.MatchString(key.LineComment)
+}
+
+// imageRepository возвращает образ без тега и дайджеста: registry/repo.
+func imageRepository(s string) string {
+	if at := strings.Index(s, "@"); at != -1 {
+		s = s[:at]
+	}
+	slash := strings.LastIndex(s, "/")
+	if colon := strings.LastIndex(s, ":"); colon > slash {
+		s = s[:colon]
+	}
+	return s
+}
+
+func imageTag(s string) (string, bool) {
+	if strings.Contains(s, "@") {
+		return "", false
+	}
+	name := s[strings.LastIndex(s, "/")+1:]
+	colon := strings.LastIndex(name, ":")
+	if colon == -1 {
+		return "", false
+	}
+	return name[colon+1:], true
+}
+
+// RegistryPolicy определяет, как образы из реестра должны быть закреплены.
+type RegistryPolicy string
+
+const (
+	// PolicyTagOK допускает образы по тегу.
+	PolicyTagOK RegistryPolicy = "tag-ok"
+	// PolicyDigestOnly требует закрепления образа по дайджесту (@sha256:...).
+	PolicyDigestOnly RegistryPolicy = "digest-only"
+)
+
+// DefaultRegistry — реестр образов, разрешённый по умолчанию.
+const DefaultRegistry = "registry.bigbrother.io"
+
+// DefaultOptions возвращает настройки, с которыми работает CLI без флагов.
+func DefaultOptions() Options {
+	return Options{Registries: []string{DefaultRegistry}}
+}
+
+// DefaultMaxCPUCores — порог подсказки про милли-ядра по умолчанию.
+const DefaultMaxCPUCores = 64
+
+func (o Options) maxCPUCores() int64 {
+	if o.MaxCPUCores > 0 {
+		return o.MaxCPUCores
+	}
+	return DefaultMaxCPUCores
+}
+
+// DefaultMaxDepth — допустимая глубина вложенности документа по умолчанию,
+// с большим запасом для любого реального пода.
+const DefaultMaxDepth = 100
+
+func (o Options) maxDepth() int {
+	if o.MaxDepth > 0 {
+		return o.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+func (o Options) allowsRegistry(host string) bool {
+	if len(o.Registries) == 0 {
+		return host == DefaultRegistry
+	}
+	for _, r := range o.Registries {
+		if host == r {
+			return true
+		}
+	}
+	return false
+}
+
+// imageRegistry возвращает хост реестра — часть образа до первого '/'.
+func imageRegistry(s string) (host, rest string, ok bool) {
+	slash := strings.Index(s, "/")
+	if slash == -1 {
+		return "", "", false
+	}
+	return s[:slash], s[slash+1:], true
+}
+
+func isValidImage(s string, opts Options) bool {
+	host, rest, ok := imageRegistry(s)
+	if !ok || !opts.allowsRegistry(host) {
+		return false
+	}
+	colon := strings.LastIndex(rest, ":")
+	if colon == -1 {
+		return false
+	}
+	tag := rest[colon+1:]
+	return tag != ""
+}
@@ -0,0 +1,502 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// containerPod — начало пода с одним контейнером; случаи дописывают
+// оставшиеся поля контейнера с отступом в шесть пробелов.
+const containerPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:v1
+`
+
+type wantError struct {
+	code string
+	msg  string
+}
+
+type validateCase struct {
+	name    string
+	content string
+	want    []wantError
+}
+
+// runCases проверяет каждый случай через Validate с DefaultOptions и
+// сравнивает коды и сообщения всех ошибок по порядку. Каждый выданный
+// код должен быть в Checks.
+func runCases(t *testing.T, cases []validateCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs, err := Validate([]byte(tc.content), DefaultOptions())
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			got := make([]wantError, len(errs))
+			for i, e := range errs {
+				got[i] = wantError{code: e.Code, msg: e.Msg}
+				if !isListedCheck(e.Code) {
+					t.Errorf("code %q is not listed in Checks", e.Code)
+				}
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("error %d: got %v, want %v", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestContainerPortProtocolUniqueness(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name: "same number with TCP and UDP",
+			content: containerPod + `      resources: {}
+      ports:
+        - containerPort: 53
+          protocol: TCP
+        - containerPort: 53
+          protocol: UDP
+`,
+		},
+		{
+			name: "same tuple twice",
+			content: containerPod + `      resources: {}
+      ports:
+        - containerPort: 53
+          protocol: UDP
+        - containerPort: 53
+          protocol: UDP
+`,
+			want: []wantError{{"containerPort-duplicate", "duplicate containerPort 53/UDP"}},
+		},
+		{
+			name: "default protocol is TCP",
+			content: containerPod + `      resources: {}
+      ports:
+        - containerPort: 8080
+        - containerPort: 8080
+          protocol: TCP
+`,
+			want: []wantError{{"containerPort-duplicate", "duplicate containerPort 8080/TCP"}},
+		},
+	})
+}
+
+func TestEmptyMetadata(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name: "empty mapping",
+			content: `apiVersion: v1
+kind: Pod
+metadata: {}
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:v1
+      resources: {}
+`,
+			want: []wantError{{"name-required", "name is required"}},
+		},
+	})
+}
+
+func TestNullOptionalObjects(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name: "bare labels",
+			content: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels:
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:v1
+      resources: {}
+`,
+		},
+		{
+			name: "bare annotations",
+			content: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  annotations:
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:v1
+      resources: {}
+`,
+		},
+		{
+			name: "labels as list",
+			content: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels: []
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:v1
+      resources: {}
+`,
+			want: []wantError{{"labels-type", "labels must be object"}},
+		},
+	})
+}
+
+func TestNullRequiredObjects(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name: "null metadata",
+			content: `apiVersion: v1
+kind: Pod
+metadata:
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:v1
+      resources: {}
+`,
+			want: []wantError{{"metadata-type", "metadata must be object"}},
+		},
+		{
+			name: "null spec",
+			content: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+`,
+			want: []wantError{{"spec-type", "spec must be object"}},
+		},
+		{
+			name:    "null resources",
+			content: containerPod + "      resources:\n",
+			want:    []wantError{{"resources-type", "resources must be object"}},
+		},
+	})
+}
+
+func isListedCheck(code string) bool {
+	for _, c := range Checks {
+		if c.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	literalCode = regexp.MustCompile(`Code:\s+"([^"]+)",`)
+	dynamicCode = regexp.MustCompile(`Code:\s+[^,\n]*\+ "([^"]+)",`)
+)
+
+// TestChecksMatchEmittedCodes сверяет Checks с кодами в исходниках
+// валидаторов: каждый литеральный код должен быть в списке, а каждый код
+// из списка — выдаваться литералом или одним из составных кодов вида
+// field + "-suffix".
+func TestChecksMatchEmittedCodes(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	literals := map[string]bool{}
+	var suffixes []string
+	for _, name := range files {
+		if name == "checks.go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range literalCode.FindAllSubmatch(src, -1) {
+			literals[string(m[1])] = true
+		}
+		for _, m := range dynamicCode.FindAllSubmatch(src, -1) {
+			suffixes = append(suffixes, string(m[1]))
+		}
+	}
+	for code := range literals {
+		if !isListedCheck(code) {
+			t.Errorf("code %q is emitted but not listed in Checks", code)
+		}
+	}
+	seen := map[string]bool{}
+	for _, c := range Checks {
+		if seen[c.Code] {
+			t.Errorf("code %q is listed twice", c.Code)
+		}
+		seen[c.Code] = true
+		if literals[c.Code] {
+			continue
+		}
+		emitted := false
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(c.Code, suffix) {
+				emitted = true
+				break
+			}
+		}
+		if !emitted {
+			t.Errorf("code %q is listed in Checks but never emitted", c.Code)
+		}
+	}
+}
+
+func TestImageRegistryHost(t *testing.T) {
+	pod := func(image string) string {
+		return `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: ` + image + `
+      resources: {}
+`
+	}
+	runCases(t, []validateCase{
+		{
+			name:    "exact host",
+			content: pod("registry.bigbrother.io/app:v1"),
+		},
+		{
+			name:    "host as prefix of another host",
+			content: pod("registry.bigbrother.io.evil.com/app:v1"),
+			want:    []wantError{{"image-format", "image has invalid format 'registry.bigbrother.io.evil.com/app:v1'"}},
+		},
+	})
+}
+
+func TestNullPorts(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name:    "null ports",
+			content: containerPod + "      resources: {}\n      ports:\n",
+		},
+		{
+			name:    "ports as mapping",
+			content: containerPod + "      resources: {}\n      ports: {}\n",
+			want:    []wantError{{"ports-type", "ports must be array"}},
+		},
+	})
+}
+
+func TestBareBytesMemory(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name:    "quoted bytes",
+			content: containerPod + "      resources:\n        limits:\n          memory: \"134217728\"\n",
+		},
+		{
+			name:    "bytes with unknown suffix",
+			content: containerPod + "      resources:\n        limits:\n          memory: \"134217728b\"\n",
+			want:    []wantError{{"memory-format", "memory has invalid format '134217728b'"}},
+		},
+	})
+}
+
+func TestParseMemoryBytes(t *testing.T) {
+	for s, want := range map[string]int64{
+		"134217728": 134217728,
+		"0":         0,
+		"128Mi":     128 << 20,
+	} {
+		if got, ok := ParseMemory(s); !ok || got != want {
+			t.Errorf("ParseMemory(%q) = %d, %v; want %d, true", s, got, ok, want)
+		}
+	}
+}
+
+func TestPortValueCallers(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name:    "containerPort out of range",
+			content: containerPod + "      resources: {}\n      ports:\n        - containerPort: 70000\n",
+			want:    []wantError{{"containerPort-range", "containerPort value out of range"}},
+		},
+		{
+			name:    "containerPort quoted",
+			content: containerPod + "      resources: {}\n      ports:\n        - containerPort: \"8080\"\n",
+			want:    []wantError{{"containerPort-type", "containerPort must be int, remove the quotes around '8080'"}},
+		},
+		{
+			name:    "containerPort named",
+			content: containerPod + "      resources: {}\n      ports:\n        - containerPort: http\n",
+			want:    []wantError{{"containerPort-type", "containerPort must be int"}},
+		},
+		{
+			name: "httpGet named port",
+			content: containerPod + `      resources: {}
+      ports:
+        - containerPort: 8080
+          name: http
+      readinessProbe:
+        httpGet:
+          path: /healthz
+          port: http
+`,
+		},
+		{
+			name: "httpGet port out of range",
+			content: containerPod + `      resources: {}
+      readinessProbe:
+        httpGet:
+          path: /healthz
+          port: 0
+`,
+			want: []wantError{{"port-range", "port value out of range"}},
+		},
+		{
+			name: "tcpSocket invalid port name",
+			content: containerPod + `      resources: {}
+      livenessProbe:
+        tcpSocket:
+          port: Web_Port
+`,
+			want: []wantError{{"port-format", "port has invalid format 'Web_Port'"}},
+		},
+		{
+			name: "tcpSocket boolean port",
+			content: containerPod + `      resources: {}
+      livenessProbe:
+        tcpSocket:
+          port: true
+`,
+			want: []wantError{{"port-type", "port must be int or string"}},
+		},
+		{
+			name: "lifecycle httpGet port out of range",
+			content: containerPod + `      resources: {}
+      lifecycle:
+        preStop:
+          httpGet:
+            path: /stop
+            port: 65536
+`,
+			want: []wantError{{"port-range", "port value out of range"}},
+		},
+	})
+}
+
+func TestProbeTimeoutExceedsPeriod(t *testing.T) {
+	content := containerPod + `      resources: {}
+      livenessProbe:
+        exec:
+          command: [check]
+        timeoutSeconds: 10
+        periodSeconds: 5
+`
+	errs, err := Validate([]byte(content), DefaultOptions())
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("without --lint: got %v, %v; want no errors", errs, err)
+	}
+	opts := DefaultOptions()
+	opts.Lint = true
+	errs, err = Validate([]byte(content), opts)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	want := ValidationError{
+		Line:     10,
+		Code:     "timeoutSeconds-exceeds-period",
+		Msg:      "timeoutSeconds (10) should not exceed periodSeconds (5)",
+		Severity: SeverityWarning,
+	}
+	if len(errs) != 1 || errs[0] != want {
+		t.Errorf("with --lint: got %v, want %v", errs, want)
+	}
+}
+
+func TestDeeplyNestedDocument(t *testing.T) {
+	nested := strings.Repeat("[", 5000) + strings.Repeat("]", 5000)
+	runCases(t, []validateCase{
+		{
+			name:    "nested sequences",
+			content: containerPod + "      resources: {}\n      args: " + nested + "\n",
+			want:    []wantError{{"document-depth", "document nesting too deep"}},
+		},
+	})
+}
+
+func TestEmptySpec(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name: "empty mapping",
+			content: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec: {}
+`,
+			want: []wantError{{"containers-required", "containers is required"}},
+		},
+	})
+}
+
+func TestImageWhitespace(t *testing.T) {
+	runCases(t, []validateCase{
+		{
+			name: "space in repository",
+			content: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/my app:v1
+      resources: {}
+`,
+			want: []wantError{{"image-whitespace", "image must not contain whitespace"}},
+		},
+	})
+}
+
+func TestNonDecimalCPU(t *testing.T) {
+	cpu := func(v string) string {
+		return containerPod + "      resources:\n        limits:\n          cpu: " + v + "\n"
+	}
+	runCases(t, []validateCase{
+		{
+			name:    "decimal",
+			content: cpu("2"),
+		},
+		{
+			name:    "hex",
+			content: cpu("0x10"),
+			want:    []wantError{{"cpu-format", "cpu must be a decimal integer"}},
+		},
+		{
+			name:    "octal",
+			content: cpu("0o17"),
+			want:    []wantError{{"cpu-format", "cpu must be a decimal integer"}},
+		},
+		{
+			name:    "boolean",
+			content: cpu("true"),
+			want:    []wantError{{"cpu-type", "cpu must be int"}},
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"go_task2/validator"
+)
+
+// watchInterval — период опроса файла в режиме --watch.
+const watchInterval = 500 * time.Millisecond
+
+// watchFile проверяет файл при каждом изменении времени модификации или
+// размера и печатает результат с отметкой времени. Файл больше maxSize
+// байт (если maxSize > 0) не читается. Работает до прерывания; ошибки
+// проверки и чтения только печатаются, а прерывание завершает режим с
+// нулевым кодом.
+func watchFile(w io.Writer, filename string, opts validator.Options, color bool, maxSize int64) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	var lastMod time.Time
+	lastSize := int64(-1)
+	for first := true; ; first = false {
+		if !first {
+			select {
+			case <-interrupt:
+				return
+			case <-time.After(watchInterval):
+			}
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			if lastSize != -2 {
+				fmt.Fprintf(w, "[%s] %v\n", time.Now().Format(time.TimeOnly), err)
+			}
+			lastSize = -2
+			continue
+		}
+		if info.ModTime().Equal(lastMod) && info.Size() == lastSize {
+			continue
+		}
+		lastMod, lastSize = info.ModTime(), info.Size()
+		fmt.Fprintf(w, "[%s] %s\n", time.Now().Format(time.TimeOnly), filename)
+		if maxSize > 0 && info.Size() > maxSize {
+			fmt.Fprintf(w, "%s: file exceeds maximum size\n", filename)
+			continue
+		}
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintln(w, err)
+			continue
+		}
+		errs, err := validator.Validate(content, opts)
+		if err != nil {
+			fmt.Fprintln(w, err)
+			continue
+		}
+		if len(errs) == 0 {
+			fmt.Fprintln(w, "ok")
+			continue
+		}
+		printErrors(w, filepath.Base(filename), errs, color, false)
+	}
+}